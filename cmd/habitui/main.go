@@ -1,16 +1,30 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"time"
 
+	"github.com/bShaak/habitui/internal/cli"
+	"github.com/bShaak/habitui/internal/models"
+	"github.com/bShaak/habitui/internal/storage"
+	"github.com/bShaak/habitui/internal/theme"
 	"github.com/bShaak/habitui/internal/view"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
 func main() {
-	m := view.InitViewState()
-	p := tea.NewProgram(m, tea.WithAltScreen(), tea.WithReportFocus())
+	rollover, err := configuredDayRollover()
+	if len(os.Args) > 1 && cli.IsCommand(os.Args[1]) {
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v; keeping the previous day boundary\n", err)
+		}
+		os.Exit(runCLI(os.Args[1:], rollover))
+	}
+
+	m := view.InitViewState(rollover)
+	p := tea.NewProgram(m, tea.WithAltScreen(), tea.WithReportFocus(), tea.WithMouseCellMotion())
 	finalModel, err := p.Run()
 	if fm, ok := finalModel.(view.Model); ok {
 		_ = fm.Close()
@@ -20,3 +34,32 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+func runCLI(args []string, rollover *time.Duration) int {
+	store, err := storage.OpenSQLite(rollover)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error opening database: %v\n", err)
+		return 1
+	}
+	defer store.Close()
+	if err := cli.Run(context.Background(), store, args, os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// configuredDayRollover reads day_ends_at from the config for the store to
+// open with. It is nil when the config or the value can't be read, so the
+// store keeps the rollover its days were computed with.
+func configuredDayRollover() (*time.Duration, error) {
+	config, err := theme.LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+	rollover, err := models.ParseDayRollover(config.DayEndsAt)
+	if err != nil {
+		return nil, err
+	}
+	return &rollover, nil
+}
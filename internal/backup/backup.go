@@ -0,0 +1,412 @@
+// Package backup converts the habit database to and from a versioned JSON document.
+package backup
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bShaak/habitui/internal/models"
+	"github.com/bShaak/habitui/internal/storage"
+)
+
+// FormatVersion is the document version written by Export.
+// Version 2 added quantity habits (kind, unit, target) and completion values;
+// version 3 added structured recurrences; version 4 added tags; version 5
+// added skipped days and vacations; version 6 added the manual sort order.
+const FormatVersion = 6
+
+type Document struct {
+	Version     int          `json:"version"`
+	ExportedAt  string       `json:"exported_at"`
+	Habits      []Habit      `json:"habits"`
+	Completions []Completion `json:"completions"`
+	Skips       []Skip       `json:"skips,omitempty"`
+	Vacations   []Vacation   `json:"vacations,omitempty"`
+}
+
+type Habit struct {
+	ID          int64              `json:"id"`
+	Name        string             `json:"name"`
+	Description string             `json:"description,omitempty"`
+	Frequency   string             `json:"frequency"`
+	Recurrence  *models.Recurrence `json:"recurrence,omitempty"`
+	Goal        int                `json:"goal"`
+	Kind        string             `json:"kind,omitempty"`
+	Unit        string             `json:"unit,omitempty"`
+	Target      float64            `json:"target,omitempty"`
+	Color       string             `json:"color"`
+	Icon        string             `json:"icon,omitempty"`
+	Tags        []string           `json:"tags,omitempty"`
+	StartDate   string             `json:"start_date"`
+	CreatedAt   string             `json:"created_at"`
+	UpdatedAt   string             `json:"updated_at"`
+	ArchivedAt  string             `json:"archived_at,omitempty"`
+	SortOrder   int                `json:"sort_order,omitempty"`
+}
+
+type Completion struct {
+	ID          int64   `json:"id"`
+	HabitID     int64   `json:"habit_id"`
+	CompletedAt string  `json:"completed_at"`
+	Value       float64 `json:"value,omitempty"`
+}
+
+// Skip is a skipped day; HabitID 0 skips every habit.
+type Skip struct {
+	HabitID int64  `json:"habit_id"`
+	Day     string `json:"day"`
+}
+
+type Vacation struct {
+	StartDay string `json:"start_day"`
+	EndDay   string `json:"end_day"`
+	Note     string `json:"note,omitempty"`
+}
+
+// ImportResult summarizes what Import wrote.
+type ImportResult struct {
+	HabitsCreated      int
+	HabitsMatched      int
+	CompletionsCreated int
+	CompletionsSkipped int
+	SkipsCreated       int
+	VacationsCreated   int
+}
+
+func habitFromModel(h models.Habit) Habit {
+	var recurrence *models.Recurrence
+	if !h.Recurrence.IsWeekdays() {
+		r := h.Recurrence
+		recurrence = &r
+	}
+	return Habit{
+		Recurrence:  recurrence,
+		ID:          h.ID,
+		Name:        h.Name,
+		Description: h.Description,
+		Frequency:   h.Frequency,
+		Goal:        h.Goal,
+		Kind:        h.Kind,
+		Unit:        h.Unit,
+		Target:      h.Target,
+		Color:       h.Color,
+		Icon:        h.Icon,
+		Tags:        h.Tags,
+		StartDate:   h.StartDate,
+		CreatedAt:   h.CreatedAt,
+		UpdatedAt:   h.UpdatedAt,
+		ArchivedAt:  h.ArchivedAt,
+		SortOrder:   h.SortOrder,
+	}
+}
+
+func (h Habit) model() models.Habit {
+	var recurrence models.Recurrence
+	if h.Recurrence != nil {
+		recurrence = *h.Recurrence
+	}
+	return models.Habit{
+		Recurrence:  recurrence,
+		Name:        h.Name,
+		Description: h.Description,
+		Frequency:   h.Frequency,
+		Goal:        h.Goal,
+		Kind:        h.Kind,
+		Unit:        h.Unit,
+		Target:      h.Target,
+		Color:       h.Color,
+		Icon:        h.Icon,
+		Tags:        h.Tags,
+		StartDate:   h.StartDate,
+		CreatedAt:   h.CreatedAt,
+		UpdatedAt:   h.UpdatedAt,
+		ArchivedAt:  h.ArchivedAt,
+	}
+}
+
+// Export collects every habit (active and archived) and every completion.
+func Export(ctx context.Context, store storage.Store) (*Document, error) {
+	active, err := store.ListHabits(ctx)
+	if err != nil {
+		return nil, err
+	}
+	archived, err := store.ListArchivedHabits(ctx)
+	if err != nil {
+		return nil, err
+	}
+	completions, err := store.ListCompletions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	skips, err := store.ListSkips(ctx)
+	if err != nil {
+		return nil, err
+	}
+	vacations, err := store.ListVacations(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &Document{
+		Version:     FormatVersion,
+		ExportedAt:  time.Now().Format(time.RFC3339),
+		Habits:      make([]Habit, 0, len(active)+len(archived)),
+		Completions: make([]Completion, 0, len(completions)),
+	}
+	for _, h := range active {
+		doc.Habits = append(doc.Habits, habitFromModel(h))
+	}
+	for _, h := range archived {
+		doc.Habits = append(doc.Habits, habitFromModel(h))
+	}
+	for _, c := range completions {
+		doc.Completions = append(doc.Completions, Completion{
+			ID:          c.ID,
+			HabitID:     c.HabitID,
+			CompletedAt: c.CompletedAt,
+			Value:       c.Value,
+		})
+	}
+	for _, s := range skips {
+		doc.Skips = append(doc.Skips, Skip{HabitID: s.HabitID, Day: s.Day})
+	}
+	for _, v := range vacations {
+		doc.Vacations = append(doc.Vacations, Vacation{StartDay: v.StartDay, EndDay: v.EndDay, Note: v.Note})
+	}
+	return doc, nil
+}
+
+// Write encodes doc as indented JSON.
+func Write(w io.Writer, doc *Document) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(doc)
+}
+
+// Read decodes and validates a document produced by Write.
+func Read(r io.Reader) (*Document, error) {
+	var doc Document
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("decode backup: %w", err)
+	}
+	if err := doc.validate(); err != nil {
+		return nil, err
+	}
+	return &doc, nil
+}
+
+func (d *Document) validate() error {
+	if d.Version < 1 || d.Version > FormatVersion {
+		return fmt.Errorf("unsupported backup version %d (this build reads up to %d)", d.Version, FormatVersion)
+	}
+	ids := make(map[int64]bool, len(d.Habits))
+	for i, h := range d.Habits {
+		if h.ID == 0 {
+			return fmt.Errorf("habit #%d has no id", i+1)
+		}
+		if ids[h.ID] {
+			return fmt.Errorf("duplicate habit id %d", h.ID)
+		}
+		if strings.TrimSpace(h.Name) == "" {
+			return fmt.Errorf("habit %d has no name", h.ID)
+		}
+		if _, err := time.Parse(time.RFC3339, h.StartDate); err != nil {
+			return fmt.Errorf("habit %d has invalid start_date %q", h.ID, h.StartDate)
+		}
+		if h.Color != "" {
+			if _, err := models.NormalizeColor(h.Color); err != nil {
+				return fmt.Errorf("habit %d: %w", h.ID, err)
+			}
+		}
+		if _, err := models.NormalizeIcon(h.Icon); err != nil {
+			return fmt.Errorf("habit %d: %w", h.ID, err)
+		}
+		if h.Recurrence != nil {
+			if _, err := h.Recurrence.Normalize(); err != nil {
+				return fmt.Errorf("habit %d: %w", h.ID, err)
+			}
+		}
+		ids[h.ID] = true
+	}
+	for i, c := range d.Completions {
+		if !ids[c.HabitID] {
+			return fmt.Errorf("completion #%d references unknown habit %d", i+1, c.HabitID)
+		}
+		if _, err := time.Parse(time.RFC3339, c.CompletedAt); err != nil {
+			return fmt.Errorf("completion #%d has invalid completed_at %q", i+1, c.CompletedAt)
+		}
+	}
+	for i, s := range d.Skips {
+		if s.HabitID != 0 && !ids[s.HabitID] {
+			return fmt.Errorf("skip #%d references unknown habit %d", i+1, s.HabitID)
+		}
+		if _, err := time.Parse(models.DayLayout, s.Day); err != nil {
+			return fmt.Errorf("skip #%d has invalid day %q", i+1, s.Day)
+		}
+	}
+	for i, v := range d.Vacations {
+		if err := (models.Vacation{StartDay: v.StartDay, EndDay: v.EndDay}).Validate(); err != nil {
+			return fmt.Errorf("vacation #%d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+func habitKey(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// completionKey identifies a check-in by habit and instant, so the same moment
+// written in local time and in UTC is treated as one completion.
+func completionKey(habitID int64, completedAt string) (string, bool) {
+	t, err := time.Parse(time.RFC3339, completedAt)
+	if err != nil {
+		return "", false
+	}
+	return fmt.Sprintf("%d@%d", habitID, t.Unix()), true
+}
+
+// Import merges doc into store. Habits are matched to existing ones by name
+// (case-insensitive, including archived habits); unmatched habits are created
+// with new IDs. Completions are remapped to the resulting IDs and skipped when
+// the same habit already has a check-in at the same instant, so importing a
+// file twice is a no-op. Skips are remapped the same way and vacations are
+// matched by their date range. New habits are added after the existing ones,
+// in the document's manual order. Nothing is written if any step fails.
+func Import(ctx context.Context, store storage.Store, doc *Document) (ImportResult, error) {
+	if doc == nil {
+		return ImportResult{}, fmt.Errorf("backup document is nil")
+	}
+	if err := doc.validate(); err != nil {
+		return ImportResult{}, err
+	}
+	var result ImportResult
+	err := store.InTx(ctx, func(tx storage.Store) error {
+		var err error
+		result, err = importDocument(ctx, tx, doc)
+		return err
+	})
+	if err != nil {
+		return ImportResult{}, err
+	}
+	return result, nil
+}
+
+// importDocument is Import inside its transaction.
+func importDocument(ctx context.Context, store storage.Store, doc *Document) (ImportResult, error) {
+	var result ImportResult
+
+	active, err := store.ListHabits(ctx)
+	if err != nil {
+		return result, err
+	}
+	archived, err := store.ListArchivedHabits(ctx)
+	if err != nil {
+		return result, err
+	}
+	existingByName := make(map[string]int64)
+	for _, h := range append(active, archived...) {
+		if _, ok := existingByName[habitKey(h.Name)]; !ok {
+			existingByName[habitKey(h.Name)] = h.ID
+		}
+	}
+
+	// CreateHabit appends, so creating in sort order keeps the manual order.
+	habits := slices.Clone(doc.Habits)
+	sort.SliceStable(habits, func(i, j int) bool { return habits[i].SortOrder < habits[j].SortOrder })
+	idMap := make(map[int64]int64, len(habits))
+	for _, h := range habits {
+		if id, ok := existingByName[habitKey(h.Name)]; ok {
+			idMap[h.ID] = id
+			result.HabitsMatched++
+			continue
+		}
+		habit := h.model()
+		created, err := store.CreateHabit(ctx, &habit)
+		if err != nil {
+			return result, fmt.Errorf("create habit %q: %w", h.Name, err)
+		}
+		idMap[h.ID] = created.ID
+		existingByName[habitKey(h.Name)] = created.ID
+		result.HabitsCreated++
+	}
+
+	completions, err := store.ListCompletions(ctx)
+	if err != nil {
+		return result, err
+	}
+	seen := make(map[string]bool, len(completions))
+	for _, c := range completions {
+		if key, ok := completionKey(c.HabitID, c.CompletedAt); ok {
+			seen[key] = true
+		}
+	}
+	for _, c := range doc.Completions {
+		habitID := idMap[c.HabitID]
+		key, _ := completionKey(habitID, c.CompletedAt)
+		if seen[key] {
+			result.CompletionsSkipped++
+			continue
+		}
+		if _, err := store.CreateCompletion(ctx, &models.Completion{
+			HabitID:     habitID,
+			CompletedAt: c.CompletedAt,
+			Value:       c.Value,
+		}); err != nil {
+			return result, fmt.Errorf("create completion: %w", err)
+		}
+		seen[key] = true
+		result.CompletionsCreated++
+	}
+
+	skips, err := store.ListSkips(ctx)
+	if err != nil {
+		return result, err
+	}
+	skipped := make(map[string]bool, len(skips))
+	for _, s := range skips {
+		skipped[fmt.Sprintf("%d@%s", s.HabitID, s.Day)] = true
+	}
+	for _, s := range doc.Skips {
+		habitID := s.HabitID
+		if habitID != 0 {
+			habitID = idMap[habitID]
+		}
+		key := fmt.Sprintf("%d@%s", habitID, s.Day)
+		if skipped[key] {
+			continue
+		}
+		if _, err := store.CreateSkip(ctx, &models.Skip{HabitID: habitID, Day: s.Day}); err != nil {
+			return result, fmt.Errorf("create skip: %w", err)
+		}
+		skipped[key] = true
+		result.SkipsCreated++
+	}
+
+	vacations, err := store.ListVacations(ctx)
+	if err != nil {
+		return result, err
+	}
+	ranges := make(map[string]bool, len(vacations))
+	for _, v := range vacations {
+		ranges[v.StartDay+"/"+v.EndDay] = true
+	}
+	for _, v := range doc.Vacations {
+		key := v.StartDay + "/" + v.EndDay
+		if ranges[key] {
+			continue
+		}
+		if _, err := store.CreateVacation(ctx, &models.Vacation{StartDay: v.StartDay, EndDay: v.EndDay, Note: v.Note}); err != nil {
+			return result, fmt.Errorf("create vacation: %w", err)
+		}
+		ranges[key] = true
+		result.VacationsCreated++
+	}
+	return result, nil
+}
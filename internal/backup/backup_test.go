@@ -0,0 +1,223 @@
+package backup_test
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bShaak/habitui/internal/backup"
+	"github.com/bShaak/habitui/internal/models"
+	"github.com/bShaak/habitui/internal/storage"
+)
+
+func openTestStore(t *testing.T) *storage.SQLiteStore {
+	t.Helper()
+	store, err := storage.OpenSQLiteAt(filepath.Join(t.TempDir(), "habit.db"), nil)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	return store
+}
+
+func seedStore(t *testing.T, store storage.Store) {
+	t.Helper()
+	ctx := context.Background()
+	if _, err := store.CreateVacation(ctx, &models.Vacation{StartDay: "2026-07-10", EndDay: "2026-07-12"}); err != nil {
+		t.Fatalf("create vacation: %v", err)
+	}
+	start := time.Date(2026, 7, 1, 9, 0, 0, 0, time.Local)
+	for _, name := range []string{"Run", "Old Challenge"} {
+		h, err := store.CreateHabit(ctx, &models.Habit{
+			Name:      name,
+			Goal:      2,
+			Tags:      []string{"health"},
+			StartDate: start.Format(time.RFC3339),
+		})
+		if err != nil {
+			t.Fatalf("create habit: %v", err)
+		}
+		for d := 0; d < 3; d++ {
+			if _, err := store.CreateCompletion(ctx, &models.Completion{
+				HabitID:     h.ID,
+				CompletedAt: start.AddDate(0, 0, d).Format(time.RFC3339),
+			}); err != nil {
+				t.Fatalf("create completion: %v", err)
+			}
+		}
+		if name == "Run" {
+			if _, err := store.CreateSkip(ctx, &models.Skip{HabitID: h.ID, Day: "2026-07-05"}); err != nil {
+				t.Fatalf("create skip: %v", err)
+			}
+		}
+		if name == "Old Challenge" {
+			if err := store.ArchiveHabit(ctx, h.ID); err != nil {
+				t.Fatalf("archive habit: %v", err)
+			}
+		}
+	}
+}
+
+func TestExportImportRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	src := openTestStore(t)
+	seedStore(t, src)
+
+	doc, err := backup.Export(ctx, src)
+	if err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	if len(doc.Habits) != 2 || len(doc.Completions) != 6 {
+		t.Fatalf("export got %d habits / %d completions, want 2 / 6", len(doc.Habits), len(doc.Completions))
+	}
+
+	var buf bytes.Buffer
+	if err := backup.Write(&buf, doc); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	parsed, err := backup.Read(&buf)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+
+	dst := openTestStore(t)
+	// Occupy the first IDs so imported habits have to be remapped.
+	if _, err := dst.CreateHabit(ctx, &models.Habit{Name: "Existing", StartDate: time.Now().Format(time.RFC3339)}); err != nil {
+		t.Fatalf("create habit: %v", err)
+	}
+	result, err := backup.Import(ctx, dst, parsed)
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if result.HabitsCreated != 2 || result.CompletionsCreated != 6 || result.SkipsCreated != 1 || result.VacationsCreated != 1 {
+		t.Fatalf("import result = %+v, want 2 habits / 6 completions / 1 skip / 1 vacation", result)
+	}
+	active, _ := dst.ListHabits(ctx)
+	skips, _ := dst.ListSkips(ctx)
+	if len(skips) != 1 || skips[0].HabitID != active[len(active)-1].ID {
+		t.Fatalf("skips = %+v, want one remapped to Run (id %d)", skips, active[len(active)-1].ID)
+	}
+
+	archived, err := dst.ListArchivedHabits(ctx)
+	if err != nil {
+		t.Fatalf("list archived: %v", err)
+	}
+	if len(archived) != 1 || archived[0].Name != "Old Challenge" {
+		t.Fatalf("archived habits = %+v, want Old Challenge", archived)
+	}
+	if len(archived[0].Tags) != 1 || archived[0].Tags[0] != "health" {
+		t.Fatalf("archived habit tags = %v, want [health]", archived[0].Tags)
+	}
+	history, err := dst.GetCompletionsByHabitID(ctx, archived[0].ID)
+	if err != nil {
+		t.Fatalf("list completions: %v", err)
+	}
+	if len(history) != 3 {
+		t.Fatalf("archived habit history = %d completions, want 3", len(history))
+	}
+
+	again, err := backup.Import(ctx, dst, parsed)
+	if err != nil {
+		t.Fatalf("second import: %v", err)
+	}
+	if again.HabitsCreated != 0 || again.HabitsMatched != 2 || again.CompletionsCreated != 0 || again.CompletionsSkipped != 6 ||
+		again.SkipsCreated != 0 || again.VacationsCreated != 0 {
+		t.Fatalf("second import result = %+v, want everything matched or skipped", again)
+	}
+}
+
+func TestImportKeepsManualOrder(t *testing.T) {
+	ctx := context.Background()
+	src := openTestStore(t)
+	var ids []int64
+	for _, name := range []string{"Run", "Read", "Stretch"} {
+		h, err := src.CreateHabit(ctx, &models.Habit{Name: name})
+		if err != nil {
+			t.Fatalf("create habit: %v", err)
+		}
+		ids = append(ids, h.ID)
+	}
+	if err := src.ReorderHabits(ctx, []int64{ids[2], ids[0], ids[1]}); err != nil {
+		t.Fatalf("reorder: %v", err)
+	}
+	doc, err := backup.Export(ctx, src)
+	if err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	// Shuffle the document so only sort_order carries the order.
+	doc.Habits[0], doc.Habits[2] = doc.Habits[2], doc.Habits[0]
+
+	dst := openTestStore(t)
+	if _, err := backup.Import(ctx, dst, doc); err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	habits, err := dst.ListHabits(ctx)
+	if err != nil {
+		t.Fatalf("list habits: %v", err)
+	}
+	var names []string
+	for _, h := range habits {
+		names = append(names, h.Name)
+	}
+	if got := strings.Join(names, ","); got != "Stretch,Run,Read" {
+		t.Fatalf("imported order = %s, want Stretch,Run,Read", got)
+	}
+}
+
+func TestImportRollsBackOnError(t *testing.T) {
+	ctx := context.Background()
+	src := openTestStore(t)
+	seedStore(t, src)
+	doc, err := backup.Export(ctx, src)
+	if err != nil {
+		t.Fatalf("export: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "habit.db")
+	dst, err := storage.OpenSQLiteAt(path, nil)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	defer dst.Close()
+	// Fail on the last completion, after habits and other check-ins were written.
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatalf("open raw db: %v", err)
+	}
+	last := doc.Completions[len(doc.Completions)-1].CompletedAt
+	if _, err := db.Exec(`CREATE TRIGGER reject_completion BEFORE INSERT ON completions WHEN NEW.completed_at = '` + last + `'
+		BEGIN SELECT RAISE(ABORT, 'rejected completion'); END`); err != nil {
+		t.Fatalf("create trigger: %v", err)
+	}
+	_ = db.Close()
+
+	if _, err := backup.Import(ctx, dst, doc); err == nil {
+		t.Fatal("expected import to fail on the rejected completion")
+	}
+	active, _ := dst.ListHabits(ctx)
+	archived, _ := dst.ListArchivedHabits(ctx)
+	completions, _ := dst.ListCompletions(ctx)
+	if len(active)+len(archived)+len(completions) != 0 {
+		t.Fatalf("failed import left %d habits and %d completions", len(active)+len(archived), len(completions))
+	}
+}
+
+func TestReadRejectsUnknownVersionAndDanglingCompletion(t *testing.T) {
+	_, err := backup.Read(strings.NewReader(`{"version": 99, "habits": [], "completions": []}`))
+	if err == nil || !strings.Contains(err.Error(), "version") {
+		t.Fatalf("expected version error, got %v", err)
+	}
+
+	_, err = backup.Read(strings.NewReader(`{
+		"version": 1,
+		"habits": [],
+		"completions": [{"id": 1, "habit_id": 7, "completed_at": "2026-07-01T09:00:00Z"}]
+	}`))
+	if err == nil || !strings.Contains(err.Error(), "unknown habit") {
+		t.Fatalf("expected dangling completion error, got %v", err)
+	}
+}
@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/bShaak/habitui/internal/backup"
+	"github.com/bShaak/habitui/internal/storage"
+)
+
+func runExport(ctx context.Context, store storage.Store, args []string, out io.Writer) error {
+	fs := newFlagSet("export", out)
+	format := fs.String("format", "json", "output format (json)")
+	output := fs.String("output", "", "write to FILE instead of stdout")
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+	if strings.ToLower(*format) != "json" {
+		return fmt.Errorf("unsupported format %q (want json)", *format)
+	}
+	doc, err := backup.Export(ctx, store)
+	if err != nil {
+		return err
+	}
+	if *output == "" || *output == "-" {
+		return backup.Write(out, doc)
+	}
+	f, err := os.Create(*output)
+	if err != nil {
+		return err
+	}
+	if err := backup.Write(f, doc); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Exported %d habits and %d completions to %s\n",
+		len(doc.Habits), len(doc.Completions), *output)
+	return nil
+}
+
+func runImport(ctx context.Context, store storage.Store, args []string, out io.Writer) error {
+	fs := newFlagSet("import", out)
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return errors.New("import takes exactly one FILE (or - for stdin)")
+	}
+
+	var r io.Reader
+	if positional[0] == "-" {
+		r = os.Stdin
+	} else {
+		f, err := os.Open(positional[0])
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+	doc, err := backup.Read(r)
+	if err != nil {
+		return err
+	}
+	result, err := backup.Import(ctx, store, doc)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Imported %d new habits (%d matched existing), %d completions (%d duplicates skipped), %d skipped days, %d vacations\n",
+		result.HabitsCreated, result.HabitsMatched, result.CompletionsCreated, result.CompletionsSkipped,
+		result.SkipsCreated, result.VacationsCreated)
+	return nil
+}
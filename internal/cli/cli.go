@@ -0,0 +1,530 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/bShaak/habitui/internal/models"
+	"github.com/bShaak/habitui/internal/storage"
+)
+
+const dateLayout = "2006-01-02"
+
+type command struct {
+	name    string
+	summary string
+	run     func(ctx context.Context, store storage.Store, args []string, out io.Writer) error
+}
+
+func commands() []command {
+	return []command{
+		{name: "list", summary: "list [--date YYYY-MM-DD] [--tag T]", run: runList},
+		{name: "done", summary: "done <name|id> [--date YYYY-MM-DD] [--amount N]", run: runDone},
+		{name: "undo", summary: "undo <name|id> [--date YYYY-MM-DD]", run: runUndo},
+		{name: "add", summary: "add --name NAME [--days mon,wed | --every N | --per-week N | --month-days 1,15] [--goal N | --target N --unit U] [--color C|#hex|0-255] [--icon I] [--description D] [--tags a,b]", run: runAdd},
+		{name: "rm", summary: "rm <name|id> --yes", run: runRemove},
+		{name: "skip", summary: "skip <name|id> | --all [--date YYYY-MM-DD]", run: runSkip},
+		{name: "vacation", summary: "vacation list | add START END [--note N] | rm <id>", run: runVacation},
+		{name: "export", summary: "export [--format json] [--output FILE]", run: runExport},
+		{name: "import", summary: "import <FILE|->", run: runImport},
+		{name: "themes", summary: "themes [--dir DIR]", run: runThemes},
+		{name: "icons", summary: "icons [--nerd-font] [QUERY]", run: runIcons},
+	}
+}
+
+// IsCommand reports whether name is a CLI subcommand rather than a TUI launch.
+func IsCommand(name string) bool {
+	if name == "help" || name == "-h" || name == "--help" {
+		return true
+	}
+	for _, c := range commands() {
+		if c.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Run executes the subcommand in args[0] against store and writes results to out.
+func Run(ctx context.Context, store storage.Store, args []string, out io.Writer) error {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		printUsage(out)
+		return nil
+	}
+	for _, c := range commands() {
+		if c.name == args[0] {
+			err := c.run(ctx, store, args[1:], out)
+			if errors.Is(err, flag.ErrHelp) {
+				return nil
+			}
+			return err
+		}
+	}
+	printUsage(out)
+	return fmt.Errorf("unknown command %q", args[0])
+}
+
+func printUsage(out io.Writer) {
+	fmt.Fprintln(out, "Usage: habitui [command]")
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "Without a command, habitui opens the interactive tracker.")
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "Commands:")
+	for _, c := range commands() {
+		fmt.Fprintf(out, "  %s\n", c.summary)
+	}
+}
+
+// parseArgs lets flags appear before or after positional arguments,
+// so "done Run --date 2026-07-01" works like "done --date 2026-07-01 Run".
+func parseArgs(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+func newFlagSet(name string, out io.Writer) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(out)
+	return fs
+}
+
+// parseDay accepts YYYY-MM-DD, "today" or "yesterday" in local time.
+func parseDay(value string, now time.Time) (time.Time, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "today":
+		return models.HabitDay(now), nil
+	case "yesterday":
+		return models.HabitDay(now).AddDate(0, 0, -1), nil
+	}
+	day, err := time.ParseInLocation(dateLayout, strings.TrimSpace(value), now.Location())
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q (want YYYY-MM-DD)", value)
+	}
+	return day, nil
+}
+
+// findHabit resolves an active habit by numeric ID or case-insensitive name.
+func findHabit(ctx context.Context, store storage.Store, ref string) (models.Habit, error) {
+	habits, err := store.ListHabits(ctx)
+	if err != nil {
+		return models.Habit{}, err
+	}
+	return matchHabit(habits, ref)
+}
+
+// findAnyHabit is findHabit over active and archived habits.
+func findAnyHabit(ctx context.Context, store storage.Store, ref string) (models.Habit, error) {
+	habits, err := store.ListHabits(ctx)
+	if err != nil {
+		return models.Habit{}, err
+	}
+	archived, err := store.ListArchivedHabits(ctx)
+	if err != nil {
+		return models.Habit{}, err
+	}
+	return matchHabit(append(habits, archived...), ref)
+}
+
+func matchHabit(habits []models.Habit, ref string) (models.Habit, error) {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return models.Habit{}, errors.New("missing habit name or id")
+	}
+	if id, err := strconv.ParseInt(ref, 10, 64); err == nil {
+		for _, h := range habits {
+			if h.ID == id {
+				return h, nil
+			}
+		}
+	}
+	var matches []models.Habit
+	for _, h := range habits {
+		if strings.EqualFold(strings.TrimSpace(h.Name), ref) {
+			matches = append(matches, h)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return models.Habit{}, fmt.Errorf("no habit named %q", ref)
+	case 1:
+		return matches[0], nil
+	default:
+		ids := make([]string, len(matches))
+		for i, h := range matches {
+			ids[i] = strconv.FormatInt(h.ID, 10)
+		}
+		return models.Habit{}, fmt.Errorf("%q matches several habits (ids %s); use an id", ref, strings.Join(ids, ", "))
+	}
+}
+
+func singleHabitArg(positional []string) (string, error) {
+	if len(positional) == 0 {
+		return "", errors.New("missing habit name or id")
+	}
+	return strings.Join(positional, " "), nil
+}
+
+func runList(ctx context.Context, store storage.Store, args []string, out io.Writer) error {
+	fs := newFlagSet("list", out)
+	date := fs.String("date", "", "day to report (YYYY-MM-DD, today, yesterday)")
+	tag := fs.String("tag", "", "only list habits with this tag")
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+	day, err := parseDay(*date, time.Now())
+	if err != nil {
+		return err
+	}
+	habits, err := store.ListHabits(ctx)
+	if err != nil {
+		return err
+	}
+	if len(habits) == 0 {
+		fmt.Fprintln(out, "No habits yet. Add one with: habitui add --name NAME")
+		return nil
+	}
+	if *tag != "" {
+		var tagged []models.Habit
+		for _, h := range habits {
+			if h.HasTag(*tag) {
+				tagged = append(tagged, h)
+			}
+		}
+		if len(tagged) == 0 {
+			return fmt.Errorf("no habits tagged %q", models.NormalizeTag(*tag))
+		}
+		habits = tagged
+	}
+	completions, err := store.GetCompletionsByDate(ctx, day)
+	if err != nil {
+		return err
+	}
+	pauses, err := storage.LoadPauses(ctx, store)
+	if err != nil {
+		return err
+	}
+	byID := make(map[int64]models.Habit, len(habits))
+	for _, h := range habits {
+		byID[h.ID] = h
+	}
+	amounts := make(map[int64]float64)
+	for _, c := range completions {
+		if h, ok := byID[c.HabitID]; ok {
+			amounts[c.HabitID] += h.CompletionAmount(c)
+		}
+	}
+
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tHABIT\tSTATUS")
+	for _, h := range habits {
+		fmt.Fprintf(tw, "%d\t%s\t%s\n", h.ID, h.Label(), dayStatus(h, amounts[h.ID], pauses, day))
+	}
+	return tw.Flush()
+}
+
+func dayStatus(h models.Habit, amount float64, pauses models.Pauses, day time.Time) string {
+	_, onVacation := pauses.VacationOn(day)
+	switch {
+	case h.TargetMet(amount):
+		return "✓ " + h.FormatProgress(amount)
+	case onVacation:
+		return "- vacation"
+	case pauses.IsSkipped(h.ID, day):
+		return "- skipped"
+	case !h.ScheduledOn(day) && amount == 0:
+		return "- not scheduled"
+	default:
+		return "✗ " + h.FormatProgress(amount)
+	}
+}
+
+func sumAmounts(h models.Habit, completions []models.Completion) float64 {
+	var amount float64
+	for _, c := range completions {
+		amount += h.CompletionAmount(c)
+	}
+	return amount
+}
+
+func runDone(ctx context.Context, store storage.Store, args []string, out io.Writer) error {
+	fs := newFlagSet("done", out)
+	date := fs.String("date", "", "day to check off (YYYY-MM-DD, today, yesterday)")
+	amount := fs.Float64("amount", 0, "amount to log for quantity habits")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	ref, err := singleHabitArg(positional)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	day, err := parseDay(*date, now)
+	if err != nil {
+		return err
+	}
+	habit, err := findHabit(ctx, store, ref)
+	if err != nil {
+		return err
+	}
+	existing, err := store.GetCompletionsByHabitIDAndDate(ctx, habit.ID, day)
+	if err != nil {
+		return err
+	}
+	logged := sumAmounts(habit, existing)
+	value := 1.0
+	if habit.IsQuantity() {
+		// Quantity habits may overshoot their target, so every entry is recorded.
+		if *amount <= 0 {
+			return fmt.Errorf("%s tracks %s; pass --amount N", habit.Label(), quantityUnit(habit))
+		}
+		value = *amount
+	} else if *amount != 0 {
+		return fmt.Errorf("%s counts check-ins; --amount only applies to quantity habits", habit.Label())
+	} else if habit.TargetMet(logged) {
+		fmt.Fprintf(out, "%s is already complete for %s (%s)\n",
+			habit.Label(), day.Format(dateLayout), habit.FormatProgress(logged))
+		return nil
+	}
+	if _, err := store.CreateCompletion(ctx, &models.Completion{
+		HabitID:     habit.ID,
+		CompletedAt: models.CompletionTimeOnDay(day, now).Format(time.RFC3339),
+		Value:       value,
+	}); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Checked off %s for %s (%s)\n",
+		habit.Label(), day.Format(dateLayout), habit.FormatProgress(logged+value))
+	return nil
+}
+
+func quantityUnit(h models.Habit) string {
+	if h.Unit == "" {
+		return "an amount"
+	}
+	return h.Unit
+}
+
+func runUndo(ctx context.Context, store storage.Store, args []string, out io.Writer) error {
+	fs := newFlagSet("undo", out)
+	date := fs.String("date", "", "day to undo (YYYY-MM-DD, today, yesterday)")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	ref, err := singleHabitArg(positional)
+	if err != nil {
+		return err
+	}
+	day, err := parseDay(*date, time.Now())
+	if err != nil {
+		return err
+	}
+	habit, err := findHabit(ctx, store, ref)
+	if err != nil {
+		return err
+	}
+	existing, err := store.GetCompletionsByHabitIDAndDate(ctx, habit.ID, day)
+	if err != nil {
+		return err
+	}
+	if len(existing) == 0 {
+		return fmt.Errorf("%s has no check-ins on %s", habit.Label(), day.Format(dateLayout))
+	}
+	latest := existing[0]
+	latestAt, _ := time.Parse(time.RFC3339, latest.CompletedAt)
+	for _, c := range existing[1:] {
+		at, err := time.Parse(time.RFC3339, c.CompletedAt)
+		if err != nil {
+			continue
+		}
+		if at.After(latestAt) || (at.Equal(latestAt) && c.ID > latest.ID) {
+			latest, latestAt = c, at
+		}
+	}
+	if err := store.DeleteCompletion(ctx, latest.ID); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Removed a check-in for %s on %s (%s)\n",
+		habit.Label(), day.Format(dateLayout), habit.FormatProgress(sumAmounts(habit, existing)-habit.CompletionAmount(latest)))
+	return nil
+}
+
+// parseDays expands a comma list such as "mon,wed" or "daily" into a Frequency value.
+func parseDays(value string) (string, error) {
+	var days []string
+	for _, part := range strings.Split(value, ",") {
+		part = strings.ToLower(strings.TrimSpace(part))
+		if part == "" {
+			continue
+		}
+		if part == "daily" {
+			return "daily", nil
+		}
+		day, err := expandDay(part)
+		if err != nil {
+			return "", err
+		}
+		days = append(days, day)
+	}
+	return models.NormalizeFrequency(days), nil
+}
+
+func expandDay(prefix string) (string, error) {
+	var match string
+	for _, d := range models.Weekdays {
+		if !strings.HasPrefix(d, prefix) {
+			continue
+		}
+		if match != "" {
+			return "", fmt.Errorf("ambiguous day %q", prefix)
+		}
+		match = d
+	}
+	if match == "" {
+		return "", fmt.Errorf("unknown day %q", prefix)
+	}
+	return match, nil
+}
+
+// parseRecurrenceFlags turns at most one of --every, --per-week and --month-days into a Recurrence.
+func parseRecurrenceFlags(every, perWeek int, monthDays string) (models.Recurrence, error) {
+	var set []models.Recurrence
+	if every != 0 {
+		set = append(set, models.Recurrence{Kind: models.RecurrenceInterval, Interval: every})
+	}
+	if perWeek != 0 {
+		set = append(set, models.Recurrence{Kind: models.RecurrenceWeekly, TimesPerWeek: perWeek})
+	}
+	if strings.TrimSpace(monthDays) != "" {
+		days, err := models.ParseMonthDays(monthDays)
+		if err != nil {
+			return models.Recurrence{}, err
+		}
+		set = append(set, models.Recurrence{Kind: models.RecurrenceMonthly, MonthDays: days})
+	}
+	switch len(set) {
+	case 0:
+		return models.Recurrence{}, nil
+	case 1:
+		return set[0].Normalize()
+	default:
+		return models.Recurrence{}, errors.New("use only one of --every, --per-week and --month-days")
+	}
+}
+
+func runAdd(ctx context.Context, store storage.Store, args []string, out io.Writer) error {
+	fs := newFlagSet("add", out)
+	name := fs.String("name", "", "habit name")
+	days := fs.String("days", "daily", "schedule, e.g. mon,wed,fri or daily")
+	every := fs.Int("every", 0, "repeat every N days from today")
+	perWeek := fs.Int("per-week", 0, "N times per week on any days")
+	monthDays := fs.String("month-days", "", "days of the month, e.g. 1,15")
+	goal := fs.Int("goal", 1, "times per day")
+	target := fs.Float64("target", 0, "daily amount; makes this a quantity habit")
+	unit := fs.String("unit", "", "unit for --target, e.g. pages or km")
+	color := fs.String("color", "red", "one of "+strings.Join(models.HabitColors, ", ")+", a hex value like #1e90ff, or 0-255")
+	icon := fs.String("icon", "", "optional emoji or glyph (see the icons command)")
+	description := fs.String("description", "", "optional description")
+	tags := fs.String("tags", "", "comma-separated tags, e.g. health,work")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	habitName := strings.TrimSpace(*name)
+	if habitName == "" {
+		habitName = strings.TrimSpace(strings.Join(positional, " "))
+	}
+	if habitName == "" {
+		return errors.New("name must not be empty")
+	}
+	if *goal < 1 {
+		return errors.New("goal must be at least 1")
+	}
+	frequency, err := parseDays(*days)
+	if err != nil {
+		return err
+	}
+	recurrence, err := parseRecurrenceFlags(*every, *perWeek, *monthDays)
+	if err != nil {
+		return err
+	}
+	habitColor, err := models.NormalizeColor(*color)
+	if err != nil {
+		return err
+	}
+	habitIcon, err := models.NormalizeIcon(*icon)
+	if err != nil {
+		return err
+	}
+	habit := models.Habit{
+		Name:        habitName,
+		Description: *description,
+		Tags:        models.ParseTags(*tags),
+		Frequency:   frequency,
+		Recurrence:  recurrence,
+		Goal:        *goal,
+		Color:       habitColor,
+		Icon:        habitIcon,
+		StartDate:   time.Now().Format(time.RFC3339),
+	}
+	if *target < 0 {
+		return errors.New("target must be greater than 0")
+	}
+	if *target > 0 {
+		habit.Kind = models.HabitKindQuantity
+		habit.Target = *target
+		habit.Unit = strings.TrimSpace(*unit)
+		habit.Goal = 1
+	} else if *unit != "" {
+		return errors.New("--unit needs --target")
+	}
+	h, err := store.CreateHabit(ctx, &habit)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Added %s (id %d)\n", h.Label(), h.ID)
+	return nil
+}
+
+func runRemove(ctx context.Context, store storage.Store, args []string, out io.Writer) error {
+	fs := newFlagSet("rm", out)
+	yes := fs.Bool("yes", false, "confirm deleting the habit and its history")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	ref, err := singleHabitArg(positional)
+	if err != nil {
+		return err
+	}
+	habit, err := findAnyHabit(ctx, store, ref)
+	if err != nil {
+		return err
+	}
+	if !*yes {
+		return fmt.Errorf("rm deletes %s and all its check-ins; pass --yes to confirm", habit.Label())
+	}
+	if err := store.DeleteHabit(ctx, habit.ID); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Deleted %s\n", habit.Label())
+	return nil
+}
@@ -0,0 +1,204 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bShaak/habitui/internal/models"
+	"github.com/bShaak/habitui/internal/storage"
+	"github.com/bShaak/habitui/internal/theme"
+)
+
+func openTestStore(t *testing.T) *storage.SQLiteStore {
+	t.Helper()
+	store, err := storage.OpenSQLiteAt(filepath.Join(t.TempDir(), "habit.db"), nil)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	return store
+}
+
+func run(t *testing.T, store storage.Store, args ...string) (string, error) {
+	t.Helper()
+	var out bytes.Buffer
+	err := Run(context.Background(), store, args, &out)
+	return out.String(), err
+}
+
+func TestAddDoneUndoRespectsGoal(t *testing.T) {
+	store := openTestStore(t)
+	ctx := context.Background()
+
+	if _, err := run(t, store, "add", "--name", "Water", "--goal", "2", "--days", "mon,wed"); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	habits, err := store.ListHabits(ctx)
+	if err != nil || len(habits) != 1 {
+		t.Fatalf("list habits: %v (%d)", err, len(habits))
+	}
+	if habits[0].Frequency != "monday,wednesday" {
+		t.Fatalf("frequency = %q, want monday,wednesday", habits[0].Frequency)
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, err := run(t, store, "done", "water", "--date", "2026-07-06"); err != nil {
+			t.Fatalf("done #%d: %v", i+1, err)
+		}
+	}
+	if _, err := run(t, store, "done", "water", "--date", "2026-07-06", "--amount", "3"); err == nil {
+		t.Fatal("expected error for --amount on a count habit")
+	}
+	out, _ := run(t, store, "list", "--date", "2026-07-06")
+	if !strings.Contains(out, "✓ 2/2") {
+		t.Fatalf("done past the goal should not add check-ins, list output:\n%s", out)
+	}
+
+	if _, err := run(t, store, "undo", "Water", "--date", "2026-07-06"); err != nil {
+		t.Fatalf("undo: %v", err)
+	}
+	out, _ = run(t, store, "list", "--date", "2026-07-06")
+	if !strings.Contains(out, "✗ 1/2") {
+		t.Fatalf("undo should remove one check-in, list output:\n%s", out)
+	}
+
+	out, _ = run(t, store, "list", "--date", "2026-07-07")
+	if !strings.Contains(out, "not scheduled") {
+		t.Fatalf("tuesday should be unscheduled, list output:\n%s", out)
+	}
+}
+
+func TestRemoveByID(t *testing.T) {
+	store := openTestStore(t)
+	if _, err := run(t, store, "add", "Read"); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	if _, err := run(t, store, "rm", "1"); err == nil {
+		t.Fatal("rm without --yes should refuse to delete")
+	}
+	if habits, _ := store.ListHabits(context.Background()); len(habits) != 1 {
+		t.Fatalf("rm without --yes deleted the habit")
+	}
+	if _, err := run(t, store, "rm", "1", "--yes"); err != nil {
+		t.Fatalf("rm: %v", err)
+	}
+	habits, err := store.ListHabits(context.Background())
+	if err != nil {
+		t.Fatalf("list habits: %v", err)
+	}
+	if len(habits) != 0 {
+		t.Fatalf("expected no habits after rm, got %d", len(habits))
+	}
+}
+
+func TestRemoveArchivedHabit(t *testing.T) {
+	store := openTestStore(t)
+	ctx := context.Background()
+	if _, err := run(t, store, "add", "Read"); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	if err := store.ArchiveHabit(ctx, 1); err != nil {
+		t.Fatalf("archive: %v", err)
+	}
+	if _, err := run(t, store, "done", "Read"); err == nil {
+		t.Fatal("done should not find an archived habit")
+	}
+	if _, err := run(t, store, "rm", "read", "--yes"); err != nil {
+		t.Fatalf("rm: %v", err)
+	}
+	archived, err := store.ListArchivedHabits(ctx)
+	if err != nil {
+		t.Fatalf("list archived habits: %v", err)
+	}
+	if len(archived) != 0 {
+		t.Fatalf("expected no archived habits after rm, got %d", len(archived))
+	}
+}
+
+func TestParseDays(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{in: "daily", want: "daily"},
+		{in: "mon,wed", want: "monday,wednesday"},
+		{in: "tu, th", want: "tuesday,thursday"},
+		{in: "mon,tue,wed,thu,fri,sat,sun", want: "daily"},
+	}
+	for _, tt := range tests {
+		got, err := parseDays(tt.in)
+		if err != nil {
+			t.Fatalf("parseDays(%q): %v", tt.in, err)
+		}
+		if got != tt.want {
+			t.Fatalf("parseDays(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+	if _, err := parseDays("t"); err == nil {
+		t.Fatal("expected ambiguous day error for \"t\"")
+	}
+}
+
+func TestUnknownCommand(t *testing.T) {
+	store := openTestStore(t)
+	if _, err := run(t, store, "frobnicate"); err == nil {
+		t.Fatal("expected error for unknown command")
+	}
+}
+
+func TestAddWithRecurrence(t *testing.T) {
+	store := openTestStore(t)
+	if _, err := run(t, store, "add", "Gym", "--per-week", "3"); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	habits, err := store.ListHabits(context.Background())
+	if err != nil {
+		t.Fatalf("list habits: %v", err)
+	}
+	if r := habits[0].Recurrence; r.Kind != models.RecurrenceWeekly || r.TimesPerWeek != 3 {
+		t.Fatalf("recurrence = %+v, want 3 per week", r)
+	}
+	if _, err := run(t, store, "add", "Both", "--every", "2", "--per-week", "3"); err == nil {
+		t.Fatal("expected error when combining recurrence flags")
+	}
+}
+
+func TestListFiltersByTag(t *testing.T) {
+	store := openTestStore(t)
+	if _, err := run(t, store, "add", "Run", "--tags", "health,outdoor"); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	if _, err := run(t, store, "add", "Read", "--tags", "learning"); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	out, err := run(t, store, "list", "--tag", "#Health")
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if !strings.Contains(out, "Run") || strings.Contains(out, "Read") {
+		t.Fatalf("list --tag health should only show Run:\n%s", out)
+	}
+	if _, err := run(t, store, "list", "--tag", "work"); err == nil {
+		t.Fatal("expected error for unused tag")
+	}
+}
+
+func TestThemesReportsInvalidFiles(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	defer theme.RegisterThemes(nil)
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "broken.json"), []byte(`{"name": "broken"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	out, err := run(t, openTestStore(t), "themes", "--dir", dir)
+	if err == nil || !strings.Contains(err.Error(), "broken.json: missing colors") {
+		t.Fatalf("themes error = %v, want broken.json reported", err)
+	}
+	if !strings.Contains(out, "* catppuccin-mocha") || strings.Contains(out, "broken") {
+		t.Fatalf("themes output:\n%s", out)
+	}
+}
@@ -0,0 +1,36 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/bShaak/habitui/internal/icons"
+	"github.com/bShaak/habitui/internal/storage"
+	"github.com/bShaak/habitui/internal/theme"
+)
+
+// runIcons searches the icon catalog by name or keyword, for picking an
+// --icon value without leaving the shell.
+func runIcons(_ context.Context, _ storage.Store, args []string, out io.Writer) error {
+	fs := newFlagSet("icons", out)
+	nerdFont := fs.Bool("nerd-font", false, "include Nerd Font glyphs (default from config)")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if config, err := theme.LoadConfig(); err == nil && config.NerdFont {
+		*nerdFont = true
+	}
+	matches := icons.Search(strings.Join(positional, " "), *nerdFont)
+	if len(matches) == 0 {
+		return fmt.Errorf("no icons match %q", strings.Join(positional, " "))
+	}
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	for _, icon := range matches {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", icon.Glyph, icon.Name, icon.Keywords)
+	}
+	return w.Flush()
+}
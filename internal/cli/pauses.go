@@ -0,0 +1,154 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/bShaak/habitui/internal/models"
+	"github.com/bShaak/habitui/internal/storage"
+)
+
+// runSkip toggles a skip for one habit, or with --all for every habit.
+func runSkip(ctx context.Context, store storage.Store, args []string, out io.Writer) error {
+	fs := newFlagSet("skip", out)
+	date := fs.String("date", "", "day to skip (YYYY-MM-DD, today, yesterday)")
+	all := fs.Bool("all", false, "skip every habit")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	day, err := parseDay(*date, time.Now())
+	if err != nil {
+		return err
+	}
+
+	var habitID int64
+	label := "all habits"
+	if *all {
+		if len(positional) > 0 {
+			return errors.New("pass a habit or --all, not both")
+		}
+	} else {
+		ref, err := singleHabitArg(positional)
+		if err != nil {
+			return err
+		}
+		habit, err := findHabit(ctx, store, ref)
+		if err != nil {
+			return err
+		}
+		habitID, label = habit.ID, habit.Label()
+	}
+
+	pauses, err := storage.LoadPauses(ctx, store)
+	if err != nil {
+		return err
+	}
+	key := models.DayKey(day)
+	if pauses.SkippedForHabit(habitID, day) {
+		if err := store.DeleteSkip(ctx, habitID, key); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "Un-skipped %s on %s\n", label, key)
+		return nil
+	}
+	if _, err := store.CreateSkip(ctx, &models.Skip{HabitID: habitID, Day: key}); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Skipped %s on %s\n", label, key)
+	return nil
+}
+
+func runVacation(ctx context.Context, store storage.Store, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return errors.New("missing subcommand (list, add or rm)")
+	}
+	switch args[0] {
+	case "list", "ls":
+		return runVacationList(ctx, store, out)
+	case "add":
+		return runVacationAdd(ctx, store, args[1:], out)
+	case "rm":
+		return runVacationRemove(ctx, store, args[1:], out)
+	default:
+		return fmt.Errorf("unknown vacation subcommand %q", args[0])
+	}
+}
+
+func runVacationList(ctx context.Context, store storage.Store, out io.Writer) error {
+	vacations, err := store.ListVacations(ctx)
+	if err != nil {
+		return err
+	}
+	if len(vacations) == 0 {
+		fmt.Fprintln(out, "No vacations yet. Add one with: habitui vacation add START END")
+		return nil
+	}
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tFROM\tTO\tDAYS\tNOTE")
+	for _, v := range vacations {
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%d\t%s\n", v.ID, v.StartDay, v.EndDay, v.Days(), v.Note)
+	}
+	return tw.Flush()
+}
+
+func runVacationAdd(ctx context.Context, store storage.Store, args []string, out io.Writer) error {
+	fs := newFlagSet("vacation add", out)
+	note := fs.String("note", "", "optional note")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 2 {
+		return errors.New("want START and END days (YYYY-MM-DD)")
+	}
+	now := time.Now()
+	start, err := parseDay(positional[0], now)
+	if err != nil {
+		return err
+	}
+	end, err := parseDay(positional[1], now)
+	if err != nil {
+		return err
+	}
+	v, err := store.CreateVacation(ctx, &models.Vacation{
+		StartDay: models.DayKey(start),
+		EndDay:   models.DayKey(end),
+		Note:     strings.TrimSpace(*note),
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Added vacation %s to %s (id %d)\n", v.StartDay, v.EndDay, v.ID)
+	return nil
+}
+
+func runVacationRemove(ctx context.Context, store storage.Store, args []string, out io.Writer) error {
+	if len(args) != 1 {
+		return errors.New("want a vacation id")
+	}
+	id, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid vacation id %q", args[0])
+	}
+	vacations, err := store.ListVacations(ctx)
+	if err != nil {
+		return err
+	}
+	for _, v := range vacations {
+		if v.ID == id {
+			if err := store.DeleteVacation(ctx, id); err != nil {
+				return err
+			}
+			fmt.Fprintf(out, "Deleted vacation %s to %s\n", v.StartDay, v.EndDay)
+			return nil
+		}
+	}
+	return fmt.Errorf("no vacation with id %d", id)
+}
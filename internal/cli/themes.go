@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/bShaak/habitui/internal/storage"
+	"github.com/bShaak/habitui/internal/theme"
+)
+
+// runThemes lists built-in and user themes and reports theme files that
+// failed to load, so a new palette can be checked before switching to it.
+func runThemes(_ context.Context, _ storage.Store, args []string, out io.Writer) error {
+	fs := newFlagSet("themes", out)
+	dir := fs.String("dir", theme.GetThemesDir(), "directory of user themes")
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+	user, loadErr := theme.LoadUserThemes(*dir)
+	theme.RegisterThemes(user)
+
+	current := ""
+	if config, err := theme.LoadConfig(); err == nil {
+		current = theme.GetTheme(config).Name
+	}
+	fromUser := make(map[string]bool, len(user))
+	for _, t := range user {
+		fromUser[t.Name] = true
+	}
+	builtIn := make(map[string]bool, len(theme.AllThemes))
+	for _, t := range theme.AllThemes {
+		builtIn[t.Name] = true
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	for _, t := range theme.Themes() {
+		marker := " "
+		if t.Name == current {
+			marker = "*"
+		}
+		source := "built-in"
+		switch {
+		case fromUser[t.Name] && builtIn[t.Name]:
+			source = "user (replaces built-in)"
+		case fromUser[t.Name]:
+			source = "user"
+		}
+		fmt.Fprintf(w, "%s %s\t%s\n", marker, t.Name, source)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if loadErr != nil {
+		return fmt.Errorf("some theme files were skipped:\n%w", loadErr)
+	}
+	return nil
+}
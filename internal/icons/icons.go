@@ -0,0 +1,431 @@
+// Package icons is the catalog behind the habit form's icon picker: named
+// emoji, plus Nerd Font glyphs for terminals patched with one.
+package icons
+
+import "strings"
+
+// Icon is one catalog entry. Keywords widen search beyond the name.
+type Icon struct {
+	Glyph    string
+	Name     string
+	Keywords string
+}
+
+// Label is how the picker lists the icon, and what its filter searches.
+func (i Icon) Label() string {
+	label := i.Glyph + " " + i.Name
+	if i.Keywords != "" {
+		label += " (" + strings.ReplaceAll(i.Keywords, " ", ", ") + ")"
+	}
+	return label
+}
+
+// Catalog returns the emoji, followed by the Nerd Font glyphs when nerdFont
+// is set.
+func Catalog(nerdFont bool) []Icon {
+	if !nerdFont {
+		return emoji
+	}
+	out := make([]Icon, 0, len(emoji)+len(nerdFontGlyphs))
+	out = append(out, emoji...)
+	return append(out, nerdFontGlyphs...)
+}
+
+// Search returns catalog entries whose name or keywords contain every word
+// of query, case-insensitively.
+func Search(query string, nerdFont bool) []Icon {
+	words := strings.Fields(strings.ToLower(query))
+	var out []Icon
+	for _, icon := range Catalog(nerdFont) {
+		haystack := strings.ToLower(icon.Name + " " + icon.Keywords)
+		match := true
+		for _, w := range words {
+			if !strings.Contains(haystack, w) {
+				match = false
+				break
+			}
+		}
+		if match {
+			out = append(out, icon)
+		}
+	}
+	return out
+}
+
+// Contains reports whether glyph is in the catalog.
+func Contains(glyph string, nerdFont bool) bool {
+	for _, icon := range Catalog(nerdFont) {
+		if icon.Glyph == glyph {
+			return true
+		}
+	}
+	return false
+}
+
+var emoji = []Icon{
+	// Exercise and sport
+	{"🏃", "running", "run jog cardio"},
+	{"🚶", "walk", "steps hike"},
+	{"💪", "strength", "gym muscle lift"},
+	{"🧘", "meditation", "yoga mindfulness calm"},
+	{"🚴", "cycling", "bike ride"},
+	{"🏊", "swimming", "swim pool"},
+	{"🤸", "stretching", "mobility gymnastics"},
+	{"🧗", "climbing", "boulder"},
+	{"🏀", "basketball", "sport"},
+	{"⚽", "soccer", "football sport"},
+	{"🏈", "american football", "sport"},
+	{"⚾", "baseball", "sport"},
+	{"🎾", "tennis", "sport"},
+	{"🏐", "volleyball", "sport"},
+	{"🏓", "table tennis", "ping pong"},
+	{"🏸", "badminton", "sport"},
+	{"🥊", "boxing", "punch"},
+	{"🥋", "martial arts", "karate judo"},
+	{"⛳", "golf", "sport"},
+	{"🎿", "skiing", "snow"},
+	{"🏂", "snowboard", "snow"},
+	{"🛹", "skateboard", "skate"},
+	{"🛼", "roller skate", "skate"},
+	{"🏄", "surfing", "surf wave"},
+	{"🚣", "rowing", "boat"},
+	{"🏇", "horse riding", "horse"},
+	{"🤾", "handball", "sport"},
+	{"🥏", "frisbee", "disc"},
+	{"🎳", "bowling", "sport"},
+	{"🏹", "archery", "bow"},
+	{"🤺", "fencing", "sport"},
+	{"🥾", "hiking", "boot trail"},
+	{"👟", "sneakers", "shoe run"},
+	{"🏆", "trophy", "win goal"},
+	{"🥇", "gold medal", "first win"},
+	{"🏅", "medal", "award"},
+
+	// Health and body
+	{"💧", "water", "drink hydrate"},
+	{"😴", "sleep", "rest bed"},
+	{"🛌", "bed", "sleep rest"},
+	{"💊", "medication", "pill vitamin"},
+	{"🩺", "checkup", "doctor health"},
+	{"🦷", "teeth", "floss dentist brush"},
+	{"🪥", "toothbrush", "teeth brush"},
+	{"🧴", "skincare", "lotion sunscreen"},
+	{"🧼", "soap", "wash hygiene"},
+	{"🚿", "shower", "wash cold"},
+	{"🛁", "bath", "relax"},
+	{"💆", "massage", "relax"},
+	{"💇", "haircut", "hair"},
+	{"🧠", "brain", "study mind"},
+	{"🫀", "heart", "cardio health"},
+	{"🫁", "lungs", "breathe breathing"},
+	{"👀", "eyes", "screen break"},
+	{"👂", "ear", "listen"},
+	{"🦶", "foot", "steps"},
+	{"🩹", "bandage", "recovery"},
+	{"🚭", "no smoking", "quit smoke"},
+	{"🚱", "no alcohol", "sober quit"},
+	{"🥤", "soda free", "drink sugar"},
+
+	// Food and drink
+	{"🥗", "salad", "nutrition vegetables healthy"},
+	{"🍎", "apple", "fruit"},
+	{"🍌", "banana", "fruit"},
+	{"🍊", "orange", "fruit"},
+	{"🍓", "strawberry", "fruit"},
+	{"🫐", "blueberries", "fruit"},
+	{"🍇", "grapes", "fruit"},
+	{"🍉", "watermelon", "fruit"},
+	{"🥑", "avocado", "fruit"},
+	{"🥦", "broccoli", "vegetables"},
+	{"🥕", "carrot", "vegetables"},
+	{"🌽", "corn", "vegetables"},
+	{"🥬", "greens", "vegetables leafy"},
+	{"🍅", "tomato", "vegetables"},
+	{"🥜", "nuts", "protein snack"},
+	{"🥚", "egg", "protein breakfast"},
+	{"🍳", "cooking", "cook breakfast"},
+	{"🥣", "breakfast", "cereal bowl"},
+	{"🍞", "bread", "bake"},
+	{"🍚", "rice", "meal"},
+	{"🍜", "noodles", "meal"},
+	{"🍲", "stew", "meal cook"},
+	{"🍱", "meal prep", "bento lunch"},
+	{"🥪", "sandwich", "lunch"},
+	{"🐟", "fish", "protein omega"},
+	{"🍗", "chicken", "protein"},
+	{"🥩", "meat", "protein"},
+	{"🧀", "cheese", "dairy"},
+	{"🥛", "milk", "dairy drink"},
+	{"🍵", "tea", "drink green"},
+	{"☕", "coffee", "caffeine drink"},
+	{"🧃", "juice", "drink"},
+	{"🍷", "wine", "alcohol"},
+	{"🍺", "beer", "alcohol"},
+	{"🍫", "chocolate", "sugar sweets"},
+	{"🍬", "candy", "sugar sweets"},
+	{"🍩", "donut", "sugar sweets"},
+	{"🍰", "cake", "sugar sweets"},
+	{"🍕", "pizza", "junk food"},
+	{"🍔", "burger", "junk food"},
+	{"🍟", "fries", "junk food"},
+	{"🧂", "salt", "cook"},
+	{"🥄", "spoon", "eat"},
+
+	// Mind and learning
+	{"📚", "reading", "books study"},
+	{"📖", "book", "read"},
+	{"📝", "journal", "write notes"},
+	{"📓", "notebook", "notes"},
+	{"📔", "diary", "journal"},
+	{"🎓", "graduation", "study degree"},
+	{"🏫", "school", "class"},
+	{"🧮", "math", "abacus"},
+	{"🔬", "science", "microscope lab"},
+	{"🔭", "astronomy", "telescope"},
+	{"🧪", "experiment", "lab"},
+	{"🧬", "biology", "dna"},
+	{"🌍", "language", "world travel"},
+	{"🈯", "japanese", "language"},
+	{"🔤", "alphabet", "language letters"},
+	{"💡", "idea", "learn think"},
+	{"🤔", "reflect", "think"},
+	{"🙏", "gratitude", "thanks pray"},
+	{"📿", "prayer", "beads"},
+	{"🧩", "puzzle", "brain game"},
+	{"🎲", "board game", "dice"},
+	{"🃏", "cards", "game"},
+	{"🎯", "focus", "target goal"},
+	{"⏰", "alarm", "wake early"},
+	{"⏳", "hourglass", "time"},
+	{"📵", "no phone", "screen digital detox"},
+	{"📴", "phone off", "detox"},
+	{"🔕", "quiet", "mute focus"},
+
+	// Work and productivity
+	{"💻", "coding", "laptop programming"},
+	{"📱", "phone", "mobile"},
+	{"📧", "email", "inbox mail"},
+	{"📨", "inbox", "email"},
+	{"📅", "calendar", "plan schedule"},
+	{"📆", "planner", "schedule"},
+	{"📋", "checklist", "todo tasks"},
+	{"✅", "done", "check todo"},
+	{"📌", "pin", "priority"},
+	{"📎", "paperclip", "admin"},
+	{"📊", "chart", "stats review"},
+	{"📈", "growth", "progress"},
+	{"💼", "work", "briefcase job"},
+	{"🧾", "receipt", "expenses"},
+	{"💰", "savings", "money"},
+	{"💵", "cash", "money budget"},
+	{"💳", "card", "spending budget"},
+	{"🏦", "bank", "money"},
+	{"🪙", "coin", "money save"},
+	{"🔧", "fix", "repair tool"},
+	{"🔨", "build", "hammer diy"},
+	{"🧰", "toolbox", "diy"},
+	{"🚀", "launch", "ship project"},
+	{"📦", "package", "ship"},
+	{"🔒", "security", "lock"},
+	{"🔑", "key", "access"},
+	{"🐛", "bug", "debug"},
+	{"🤝", "meeting", "network handshake"},
+	{"📞", "call", "phone"},
+	{"🎤", "speech", "talk practice"},
+
+	// Creative
+	{"🎵", "music", "song"},
+	{"🎶", "melody", "music"},
+	{"🎸", "guitar", "music instrument"},
+	{"🎹", "piano", "music instrument"},
+	{"🎻", "violin", "music instrument"},
+	{"🎺", "trumpet", "music instrument"},
+	{"🎷", "saxophone", "music instrument"},
+	{"🥁", "drums", "music instrument"},
+	{"🪕", "banjo", "music instrument"},
+	{"🎧", "headphones", "listen podcast"},
+	{"🎨", "painting", "art"},
+	{"📷", "photography", "camera photo"},
+	{"📸", "photo", "camera"},
+	{"🎬", "film", "video movie"},
+	{"🎥", "video", "camera"},
+	{"📺", "tv", "watch"},
+	{"🎮", "gaming", "game"},
+	{"🧶", "knitting", "yarn craft"},
+	{"🧵", "sewing", "thread craft"},
+	{"🪡", "needle", "sew craft"},
+	{"🪴", "plant", "garden"},
+	{"🌱", "seedling", "grow garden"},
+	{"🌻", "sunflower", "garden"},
+	{"🌷", "tulip", "flower"},
+	{"🌸", "blossom", "flower"},
+	{"🌳", "tree", "nature"},
+	{"🍀", "clover", "luck"},
+	{"🏺", "pottery", "ceramics craft"},
+	{"🎭", "theater", "drama act"},
+	{"💃", "dance", "dancing"},
+	{"🕺", "dancing", "dance"},
+	{"🧑", "person", "self"},
+
+	// Home and chores
+	{"🧹", "chores", "clean sweep"},
+	{"🧺", "laundry", "wash clothes"},
+	{"🧽", "dishes", "sponge clean"},
+	{"🪣", "cleaning", "bucket mop"},
+	{"🛒", "groceries", "shopping"},
+	{"🏠", "home", "house"},
+	{"🪟", "window", "clean"},
+	{"🚪", "door", "leave"},
+	{"🔥", "fire", "streak"},
+	{"🪵", "wood", "firewood"},
+	{"🚗", "car", "drive"},
+	{"⛽", "fuel", "car"},
+	{"🚌", "bus", "commute"},
+	{"🚆", "train", "commute"},
+	{"🧳", "luggage", "travel pack"},
+	{"📬", "mail", "post letters"},
+	{"🔋", "charge", "battery"},
+	{"🌞", "sunshine", "outside sun"},
+	{"🌙", "night", "evening moon"},
+	{"⭐", "star", "favorite"},
+	{"🌈", "rainbow", "happy"},
+	{"☔", "umbrella", "rain"},
+
+	// People and relationships
+	{"👪", "family", "kids"},
+	{"👶", "baby", "kids"},
+	{"🧒", "child", "kids"},
+	{"💑", "partner", "couple date"},
+	{"💌", "love letter", "note"},
+	{"📮", "postcard", "write"},
+	{"🎁", "gift", "present"},
+	{"🎉", "celebrate", "party"},
+	{"🥳", "party", "celebrate"},
+	{"😊", "smile", "happy mood"},
+	{"😌", "relief", "calm"},
+	{"🫶", "kindness", "love"},
+	{"🤗", "hug", "kindness"},
+	{"👋", "hello", "wave social"},
+	{"💬", "chat", "talk message"},
+	{"🙌", "volunteer", "help"},
+	{"💖", "love", "heart"},
+	{"💚", "green heart", "nature"},
+	{"💙", "blue heart", "calm"},
+
+	// Animals
+	{"🐕", "dog", "walk pet"},
+	{"🐈", "cat", "pet"},
+	{"🐾", "paws", "pet"},
+	{"🐎", "horse", "ride"},
+	{"🐦", "bird", "birdwatching"},
+	{"🐠", "aquarium", "fish pet"},
+	{"🐢", "turtle", "slow steady"},
+	{"🐝", "bee", "busy"},
+	{"🦋", "butterfly", "change"},
+	{"🐌", "snail", "slow"},
+	{"🦉", "owl", "night study"},
+	{"🐓", "rooster", "early morning"},
+
+	// Symbols
+	{"✨", "sparkles", "new shine"},
+	{"⚡", "energy", "power"},
+	{"💎", "gem", "quality"},
+	{"🔔", "reminder", "bell"},
+	{"🔁", "repeat", "routine"},
+	{"🆕", "new", "start"},
+	{"🆗", "ok", "fine"},
+	{"🔴", "red circle", "dot"},
+	{"🟠", "orange circle", "dot"},
+	{"🟡", "yellow circle", "dot"},
+	{"🟢", "green circle", "dot"},
+	{"🔵", "blue circle", "dot"},
+	{"🟣", "purple circle", "dot"},
+	{"⚪", "white circle", "dot"},
+	{"⚫", "black circle", "dot"},
+	{"🟥", "red square", "block"},
+	{"🟩", "green square", "block"},
+	{"🟦", "blue square", "block"},
+	{"🚫", "no", "quit stop"},
+	{"⛔", "stop", "quit"},
+	{"❌", "cross", "avoid"},
+	{"❓", "question", "ask"},
+	{"❗", "important", "alert"},
+	{"💯", "hundred", "perfect"},
+	{"🔝", "top", "best"},
+	{"🧭", "compass", "direction plan"},
+	{"🌊", "wave", "ocean swim"},
+	{"🌅", "sunrise", "morning early"},
+	{"🌄", "dawn", "morning"},
+	{"🌃", "evening", "night"},
+}
+
+// nerdFontGlyphs are Font Awesome icons at their Nerd Fonts code points; they
+// only render in terminals using a Nerd Font.
+var nerdFontGlyphs = []Icon{
+	{"", "nf music", "song"},
+	{"", "nf search", "find"},
+	{"", "nf heart", "love health"},
+	{"", "nf star", "favorite"},
+	{"", "nf check", "done"},
+	{"", "nf home", "house"},
+	{"", "nf clock", "time"},
+	{"", "nf road", "commute run"},
+	{"", "nf lock", "security"},
+	{"", "nf flag", "goal"},
+	{"", "nf headphones", "listen podcast"},
+	{"", "nf book", "read study"},
+	{"", "nf camera", "photo"},
+	{"", "nf list", "todo"},
+	{"", "nf pencil", "write"},
+	{"", "nf water", "drink hydrate"},
+	{"", "nf leaf", "nature vegan"},
+	{"", "nf fire", "streak"},
+	{"", "nf eye", "screen break"},
+	{"", "nf plane", "travel"},
+	{"", "nf calendar", "plan"},
+	{"", "nf cart", "shopping groceries"},
+	{"", "nf chart", "stats"},
+	{"", "nf trophy", "win"},
+	{"", "nf phone", "call"},
+	{"", "nf github", "code open source"},
+	{"", "nf globe", "language world"},
+	{"", "nf wrench", "fix"},
+	{"", "nf tasks", "todo"},
+	{"", "nf briefcase", "work"},
+	{"", "nf users", "social family"},
+	{"", "nf cloud", "weather"},
+	{"", "nf flask", "science"},
+	{"", "nf money", "budget save"},
+	{"", "nf envelope", "email"},
+	{"", "nf bolt", "energy"},
+	{"", "nf lightbulb", "idea"},
+	{"", "nf coffee", "caffeine"},
+	{"", "nf cutlery", "meal eat"},
+	{"", "nf medkit", "medication health"},
+	{"", "nf beer", "alcohol"},
+	{"", "nf laptop", "coding"},
+	{"", "nf smile", "mood"},
+	{"", "nf gamepad", "gaming"},
+	{"", "nf terminal", "shell coding"},
+	{"", "nf code", "programming"},
+	{"", "nf puzzle", "brain"},
+	{"", "nf microphone", "podcast speech"},
+	{"", "nf shield", "security"},
+	{"", "nf rocket", "launch"},
+	{"", "nf bullseye", "focus target"},
+	{"", "nf dollar", "money"},
+	{"", "nf sun", "morning"},
+	{"", "nf moon", "night sleep"},
+	{"", "nf graduation", "study"},
+	{"", "nf language", "translate"},
+	{"", "nf paw", "pet dog"},
+	{"", "nf tree", "nature"},
+	{"", "nf football", "soccer sport"},
+	{"", "nf newspaper", "news read"},
+	{"", "nf paint brush", "art"},
+	{"", "nf bicycle", "cycling"},
+	{"", "nf heartbeat", "cardio"},
+	{"", "nf bed", "sleep"},
+	{"", "nf shower", "wash"},
+	{"", "nf bath", "relax"},
+}
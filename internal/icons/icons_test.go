@@ -0,0 +1,42 @@
+package icons
+
+import (
+	"testing"
+
+	"github.com/bShaak/habitui/internal/models"
+)
+
+func TestCatalogGlyphsAreValidIcons(t *testing.T) {
+	seen := make(map[string]string)
+	for _, icon := range Catalog(true) {
+		if got, err := models.NormalizeIcon(icon.Glyph); err != nil || got != icon.Glyph {
+			t.Errorf("%s (%q): NormalizeIcon = %q, %v", icon.Name, icon.Glyph, got, err)
+		}
+		if prev, ok := seen[icon.Glyph]; ok {
+			t.Errorf("%q is listed as both %s and %s", icon.Glyph, prev, icon.Name)
+		}
+		seen[icon.Glyph] = icon.Name
+	}
+	if len(Catalog(false)) >= len(Catalog(true)) {
+		t.Fatal("the Nerd Font set should only be included when enabled")
+	}
+}
+
+func TestSearch(t *testing.T) {
+	got := Search("Cardio", false)
+	if len(got) == 0 || got[0].Glyph != "🏃" {
+		t.Fatalf("Search(cardio) = %v, want running first", got)
+	}
+	if got := Search("music instrument piano", false); len(got) != 1 || got[0].Name != "piano" {
+		t.Fatalf("multi-word search = %v, want only piano", got)
+	}
+	if got := Search("terminal", false); len(got) != 0 {
+		t.Fatalf("Nerd Font glyphs leaked into the emoji-only search: %v", got)
+	}
+	if got := Search("terminal", true); len(got) != 1 {
+		t.Fatalf("Search(terminal) with Nerd Fonts = %v", got)
+	}
+	if !Contains("📚", false) || Contains("\uf120", false) || !Contains("\uf120", true) {
+		t.Fatal("Contains does not match the catalog")
+	}
+}
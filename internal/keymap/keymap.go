@@ -0,0 +1,304 @@
+// Package keymap defines the TUI's key bindings and applies overrides from the
+// "keys" section of habitui.config.
+package keymap
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+)
+
+// KeyMap holds every rebindable action. Screens share actions where the
+// meaning is the same (Up, Prev, Confirm, ...), so one override applies
+// everywhere. ctrl+c always quits and esc always goes back.
+type KeyMap struct {
+	Up       key.Binding
+	Down     key.Binding
+	Left     key.Binding
+	Right    key.Binding
+	MoveUp   key.Binding
+	MoveDown key.Binding
+	Prev     key.Binding
+	Next     key.Binding
+	PrevTab  key.Binding
+	NextTab  key.Binding
+	PageUp   key.Binding
+	PageDown key.Binding
+
+	Toggle    key.Binding
+	Increment key.Binding
+	Decrement key.Binding
+	Add       key.Binding
+	Edit      key.Binding
+	Delete    key.Binding
+	Archive   key.Binding
+	Restore   key.Binding
+	Confirm   key.Binding
+	Cancel    key.Binding
+
+	Calendar  key.Binding
+	Stats     key.Binding
+	Heatmap   key.Binding
+	Archived  key.Binding
+	Vacations key.Binding
+	DayLog    key.Binding
+	Details   key.Binding
+
+	Sort        key.Binding
+	TagFilter   key.Binding
+	Search      key.Binding
+	Theme       key.Binding
+	SkipHabit   key.Binding
+	SkipAll     key.Binding
+	CustomRange key.Binding
+
+	Help key.Binding
+	Back key.Binding
+	Quit key.Binding
+}
+
+func binding(desc string, keys ...string) key.Binding {
+	return key.NewBinding(key.WithKeys(keys...), key.WithHelp(helpKeys(keys), desc))
+}
+
+// Default returns the built-in bindings.
+func Default() KeyMap {
+	return KeyMap{
+		Up:       binding("up", "k"),
+		Down:     binding("down", "j"),
+		Left:     binding("previous day", "h"),
+		Right:    binding("next day", "l"),
+		MoveUp:   binding("move up", "K"),
+		MoveDown: binding("move down", "J"),
+		Prev:     binding("previous", "H"),
+		Next:     binding("next", "L"),
+		PrevTab:  binding("previous tab", "left"),
+		NextTab:  binding("next tab", "right"),
+		PageUp:   binding("page up", "pgup", "ctrl+u"),
+		PageDown: binding("page down", "pgdown", "ctrl+d"),
+
+		Toggle:    binding("toggle", "enter"),
+		Increment: binding("add one", "+"),
+		Decrement: binding("remove one", "-"),
+		Add:       binding("add", "a"),
+		Edit:      binding("edit", "e"),
+		Delete:    binding("delete", "x"),
+		Archive:   binding("archive", "z"),
+		Restore:   binding("restore", "u", "enter"),
+		Confirm:   binding("confirm", "y", "enter"),
+		Cancel:    binding("cancel", "n"),
+
+		Calendar:  binding("calendar", "c"),
+		Stats:     binding("stats", "s"),
+		Heatmap:   binding("year", "y"),
+		Archived:  binding("archived", "A"),
+		Vacations: binding("vacations", "v"),
+		DayLog:    binding("day log", "d"),
+		Details:   binding("details", "i"),
+
+		Sort:        binding("sort", "o"),
+		TagFilter:   binding("tag", "#"),
+		Search:      binding("filter", "/"),
+		Theme:       binding("theme", "t"),
+		SkipHabit:   binding("skip habit", "s"),
+		SkipAll:     binding("skip all", "S"),
+		CustomRange: binding("custom range", "r"),
+
+		Help: binding("help", "?"),
+		Back: binding("back", "esc"),
+		Quit: binding("quit", "q"),
+	}
+}
+
+// actions maps config names to bindings. Back is not listed: esc stays fixed
+// so there is always a way out of every screen.
+func (k *KeyMap) actions() map[string]*key.Binding {
+	return map[string]*key.Binding{
+		"up":           &k.Up,
+		"down":         &k.Down,
+		"left":         &k.Left,
+		"right":        &k.Right,
+		"move_up":      &k.MoveUp,
+		"move_down":    &k.MoveDown,
+		"prev":         &k.Prev,
+		"next":         &k.Next,
+		"prev_tab":     &k.PrevTab,
+		"next_tab":     &k.NextTab,
+		"page_up":      &k.PageUp,
+		"page_down":    &k.PageDown,
+		"toggle":       &k.Toggle,
+		"increment":    &k.Increment,
+		"decrement":    &k.Decrement,
+		"add":          &k.Add,
+		"edit":         &k.Edit,
+		"delete":       &k.Delete,
+		"archive":      &k.Archive,
+		"restore":      &k.Restore,
+		"confirm":      &k.Confirm,
+		"cancel":       &k.Cancel,
+		"calendar":     &k.Calendar,
+		"stats":        &k.Stats,
+		"heatmap":      &k.Heatmap,
+		"archived":     &k.Archived,
+		"vacations":    &k.Vacations,
+		"day_log":      &k.DayLog,
+		"details":      &k.Details,
+		"sort":         &k.Sort,
+		"tag_filter":   &k.TagFilter,
+		"search":       &k.Search,
+		"theme":        &k.Theme,
+		"skip_habit":   &k.SkipHabit,
+		"skip_all":     &k.SkipAll,
+		"custom_range": &k.CustomRange,
+		"help":         &k.Help,
+		"quit":         &k.Quit,
+	}
+}
+
+// Actions lists the names accepted in the config's "keys" section.
+func Actions() []string {
+	var k KeyMap
+	var names []string
+	for name := range k.actions() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// screens lists the actions each screen reacts to, so overrides can be checked
+// for keys that already mean something else there. help, quit and paging work
+// on every screen.
+var screens = map[string][]string{
+	"main": {
+		"up", "down", "move_up", "move_down", "toggle", "increment", "decrement", "add", "edit",
+		"delete", "archive", "confirm", "cancel", "calendar", "stats", "heatmap", "archived",
+		"vacations", "day_log", "details", "sort", "tag_filter", "search", "theme",
+	},
+	"calendar": {
+		"up", "down", "left", "right", "prev", "next", "toggle", "increment", "decrement",
+		"skip_habit", "skip_all", "heatmap", "day_log", "details", "tag_filter", "search",
+	},
+	"stats":     {"prev_tab", "next_tab", "prev", "next", "custom_range", "tag_filter"},
+	"archive":   {"up", "down", "details", "restore", "delete", "confirm", "cancel"},
+	"heatmap":   {"up", "down", "prev", "next"},
+	"vacations": {"up", "down", "add", "delete", "confirm", "cancel"},
+	"day log":   {"up", "down", "left", "right", "add", "edit", "delete", "confirm", "cancel"},
+	"details":   {"up", "down", "edit", "archive", "calendar"},
+	"themes":    {"up", "down", "toggle"},
+}
+
+var everyScreen = []string{"help", "quit", "page_up", "page_down"}
+
+// New returns the default bindings with overrides applied. Unknown actions,
+// empty key lists and keys that another action on the same screen already
+// uses are reported and skipped, so a typo never locks a user out.
+func New(overrides map[string][]string) (KeyMap, error) {
+	k := Default()
+	actions := k.actions()
+	var problems []string
+	overridden := make(map[string]bool)
+	for name, keys := range overrides {
+		name = strings.ToLower(strings.TrimSpace(name))
+		b, ok := actions[name]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("unknown action %q", name))
+			continue
+		}
+		var cleaned []string
+		for _, k := range keys {
+			if k = strings.TrimSpace(k); k != "" {
+				cleaned = append(cleaned, k)
+			}
+		}
+		if len(cleaned) == 0 {
+			problems = append(problems, fmt.Sprintf("action %q has no keys", name))
+			continue
+		}
+		b.SetKeys(cleaned...)
+		b.SetHelp(helpKeys(cleaned), b.Help().Desc)
+		overridden[name] = true
+	}
+	problems = append(problems, k.revertCollisions(overridden)...)
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return k, fmt.Errorf("keys: %s", strings.Join(problems, "; "))
+	}
+	return k, nil
+}
+
+// revertCollisions puts overridden actions back to their defaults when they
+// gained a key that another action on one of their screens also uses. Keys an
+// action has by default are never reported: the delete prompt deliberately
+// shares y and enter with the screen behind it.
+func (k *KeyMap) revertCollisions(overridden map[string]bool) []string {
+	defaults := Default()
+	defaultActions := defaults.actions()
+	actions := k.actions()
+	var problems []string
+	for changed := true; changed; {
+		changed = false
+		for _, screen := range sortedScreens() {
+			names := append(append([]string{}, screens[screen]...), everyScreen...)
+			for _, name := range names {
+				if !overridden[name] {
+					continue
+				}
+				if other, key := collision(name, names, actions, defaultActions); other != "" {
+					problems = append(problems, fmt.Sprintf("action %q: %q is already %q on the %s screen", name, key, other, screen))
+					*actions[name] = *defaultActions[name]
+					delete(overridden, name)
+					changed = true
+				}
+			}
+		}
+	}
+	return problems
+}
+
+// collision finds a key that name gained through its override and that another
+// of names also uses.
+func collision(name string, names []string, actions, defaults map[string]*key.Binding) (string, string) {
+	for _, key := range actions[name].Keys() {
+		if slices.Contains(defaults[name].Keys(), key) {
+			continue
+		}
+		for _, other := range names {
+			if other != name && slices.Contains(actions[other].Keys(), key) {
+				return other, key
+			}
+		}
+	}
+	return "", ""
+}
+
+func sortedScreens() []string {
+	names := make([]string, 0, len(screens))
+	for name := range screens {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+var keySymbols = map[string]string{
+	"left":  "←",
+	"right": "→",
+	"up":    "↑",
+	"down":  "↓",
+}
+
+// helpKeys formats keys for help lines, e.g. "u/enter" or "←".
+func helpKeys(keys []string) string {
+	shown := make([]string, len(keys))
+	for i, k := range keys {
+		if sym, ok := keySymbols[k]; ok {
+			k = sym
+		}
+		shown[i] = k
+	}
+	return strings.Join(shown, "/")
+}
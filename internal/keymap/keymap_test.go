@@ -0,0 +1,92 @@
+package keymap
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func press(r rune) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}}
+}
+
+func TestNewAppliesOverrides(t *testing.T) {
+	k, err := New(map[string][]string{"up": {"e"}, "Down": {" n ", "down"}, "edit": {"E"}, "cancel": {"N"}})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if !key.Matches(press('e'), k.Up) || key.Matches(press('k'), k.Up) {
+		t.Fatal("up should be rebound from k to e")
+	}
+	if !key.Matches(press('n'), k.Down) {
+		t.Fatal("down should match n")
+	}
+	if got := k.Down.Help().Key; got != "n/↓" {
+		t.Fatalf("down help key = %q, want n/↓", got)
+	}
+	if !key.Matches(press('q'), k.Quit) {
+		t.Fatal("unconfigured actions keep their defaults")
+	}
+}
+
+func TestNewReportsUnknownActions(t *testing.T) {
+	k, err := New(map[string][]string{"jump": {"g"}, "quit": {}})
+	if err == nil || !strings.Contains(err.Error(), `unknown action "jump"`) || !strings.Contains(err.Error(), `"quit" has no keys`) {
+		t.Fatalf("err = %v, want unknown action and empty keys reported", err)
+	}
+	if !key.Matches(press('q'), k.Quit) {
+		t.Fatal("a rejected override must leave the default in place")
+	}
+}
+
+func TestNewReportsCollisions(t *testing.T) {
+	k, err := New(map[string][]string{"down": {"n"}, "toggle": {"enter", "g"}})
+	if err == nil || !strings.Contains(err.Error(), `action "down": "n" is already "cancel"`) {
+		t.Fatalf("err = %v, want down/cancel collision reported", err)
+	}
+	if strings.Contains(err.Error(), "toggle") {
+		t.Fatalf("err = %v; toggle keeps its default enter and adds a free key", err)
+	}
+	if !key.Matches(press('j'), k.Down) || key.Matches(press('n'), k.Down) {
+		t.Fatal("a colliding override must leave the default in place")
+	}
+	if !key.Matches(press('g'), k.Toggle) {
+		t.Fatal("a valid override next to a rejected one still applies")
+	}
+
+	// Swapping two keys is fine: only the final bindings are compared.
+	if _, err := New(map[string][]string{"up": {"j"}, "down": {"k"}}); err != nil {
+		t.Fatalf("swapping up and down: %v", err)
+	}
+	if _, err := New(nil); err != nil {
+		t.Fatalf("defaults: %v", err)
+	}
+}
+
+func TestDefaultsMatchEveryAction(t *testing.T) {
+	k := Default()
+	for name, b := range k.actions() {
+		if len(b.Keys()) == 0 || b.Help().Desc == "" {
+			t.Errorf("action %q has no default keys or help", name)
+		}
+	}
+}
+
+func TestEveryActionBelongsToAScreen(t *testing.T) {
+	used := make(map[string]bool)
+	for _, names := range screens {
+		for _, name := range names {
+			used[name] = true
+		}
+	}
+	for _, name := range everyScreen {
+		used[name] = true
+	}
+	for _, name := range Actions() {
+		if !used[name] {
+			t.Errorf("action %q is on no screen, so its overrides are never checked for collisions", name)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// MaxDayRollover bounds the day_ends_at setting; later boundaries would make
+// the morning count toward the day before.
+const MaxDayRollover = 12 * time.Hour
+
+// dayRollover is how long after midnight a day lasts. Check-ins before then
+// count toward the previous day. The store sets it once when it opens.
+var dayRollover time.Duration
+
+// SetDayRollover sets how long after midnight each day ends.
+func SetDayRollover(d time.Duration) {
+	dayRollover = d
+}
+
+// DayRollover returns how long after midnight each day ends.
+func DayRollover() time.Duration {
+	return dayRollover
+}
+
+// ParseDayRollover reads a day_ends_at value such as "03:00" or "3". An empty
+// value keeps days ending at midnight.
+func ParseDayRollover(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, nil
+	}
+	layout := "15:04"
+	if !strings.Contains(value, ":") {
+		layout = "15"
+	}
+	t, err := time.Parse(layout, value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid day_ends_at %q (want HH:MM)", value)
+	}
+	d := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+	if d >= MaxDayRollover {
+		return 0, fmt.Errorf("invalid day_ends_at %q (must be before 12:00)", value)
+	}
+	return d, nil
+}
+
+// HabitDay returns local midnight of the day the moment t counts toward: its
+// calendar day, or the day before when t falls before the rollover.
+func HabitDay(t time.Time) time.Time {
+	t = t.In(time.Local)
+	day := localDay(t)
+	if beforeRollover(t) {
+		return day.AddDate(0, 0, -1)
+	}
+	return day
+}
+
+// beforeRollover reports whether t's wall-clock time is still part of the
+// previous day. Comparing clock times keeps DST shifts out of it.
+func beforeRollover(t time.Time) bool {
+	clock := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute + time.Duration(t.Second())*time.Second
+	return clock < dayRollover
+}
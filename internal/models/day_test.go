@@ -0,0 +1,57 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDayRollover(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Duration
+		ok   bool
+	}{
+		{in: "", want: 0, ok: true},
+		{in: "03:00", want: 3 * time.Hour, ok: true},
+		{in: "3", want: 3 * time.Hour, ok: true},
+		{in: "4:30", want: 4*time.Hour + 30*time.Minute, ok: true},
+		{in: "12:00", ok: false},
+		{in: "25:00", ok: false},
+		{in: "3am", ok: false},
+	}
+	for _, tt := range tests {
+		got, err := ParseDayRollover(tt.in)
+		if (err == nil) != tt.ok || got != tt.want {
+			t.Fatalf("ParseDayRollover(%q) = %v, %v; want %v, ok=%v", tt.in, got, err, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestDayRollover(t *testing.T) {
+	defer SetDayRollover(0)
+	SetDayRollover(3 * time.Hour)
+
+	july10 := time.Date(2026, 7, 10, 0, 0, 0, 0, time.Local)
+	for _, at := range []time.Time{
+		time.Date(2026, 7, 10, 3, 0, 0, 0, time.Local),
+		time.Date(2026, 7, 10, 23, 59, 0, 0, time.Local),
+		time.Date(2026, 7, 11, 2, 59, 59, 0, time.Local),
+	} {
+		if got := HabitDay(at); !got.Equal(july10) {
+			t.Fatalf("HabitDay(%s) = %s, want 2026-07-10", at.Format(time.Kitchen), got.Format(DayLayout))
+		}
+		if got := CompletionDay(at.Format(time.RFC3339)); got != "2026-07-10" {
+			t.Fatalf("CompletionDay(%s) = %s", at.Format(time.Kitchen), got)
+		}
+	}
+
+	// Back-filling July 10 at 00:30 must land inside July 10's extended day.
+	late := time.Date(2026, 7, 20, 0, 30, 0, 0, time.Local)
+	placed := CompletionTimeOnDay(july10, late)
+	if placed.Day() != 11 || DayKey(HabitDay(placed)) != "2026-07-10" {
+		t.Fatalf("CompletionTimeOnDay = %s, want 00:30 on July 11", placed)
+	}
+	if got := CompletionTimeOnDay(july10, late.Add(12*time.Hour)); got.Day() != 10 {
+		t.Fatalf("afternoon check-ins stay on the day itself, got %s", got)
+	}
+}
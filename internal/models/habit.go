@@ -1,20 +1,262 @@
 package models
 
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/mattn/go-runewidth"
+)
+
+const (
+	HabitKindCount    = "count"    // Goal check-ins per day
+	HabitKindQuantity = "quantity" // Target amount of Unit per day
+)
+
 type Habit struct {
 	ID          int64
 	Name        string
 	Description string
-	Frequency   string // e.g. "daily" or "monday,wednesday"; default daily
-	Goal        int    // times per day; default 1
-	Color       string // red, blue, green, yellow, orange, purple, pink
-	Icon        string // optional emoji icon
+	Frequency   string     // e.g. "daily" or "monday,wednesday"; default daily
+	Recurrence  Recurrence // interval, weekly-quota and monthly schedules; zero uses Frequency
+	Goal        int        // times per day; default 1
+	Kind        string     // count (default) or quantity
+	Unit        string     // quantity habits: e.g. "pages", "km"
+	Target      float64
+	Color       string   // a HabitColors name, #rrggbb, or a 256-color index
+	Icon        string   // optional emoji or glyph; see NormalizeIcon
+	Tags        []string // normalized, sorted; see NormalizeTags
 	StartDate   string
 	CreatedAt   string
 	UpdatedAt   string
+	ArchivedAt  string // RFC3339 when archived; empty for active habits
+	SortOrder   int    // position in the manual order, ascending
 }
 
 type Completion struct {
 	ID          int64
 	HabitID     int64
 	CompletedAt string
+	LocalDate   string  // DayLayout local calendar day of CompletedAt, set by the store
+	Value       float64 // amount logged for quantity habits; 1 for count check-ins
+}
+
+// DailyTotal aggregates one habit's completions on one local day.
+type DailyTotal struct {
+	HabitID int64
+	Day     string  // DayLayout
+	Count   int     // number of check-ins
+	Value   float64 // sum of logged values
+}
+
+// Amount is the day's progress toward the habit's target: the summed value for
+// quantity habits, the check-in count otherwise.
+func (h Habit) Amount(t DailyTotal) float64 {
+	if h.IsQuantity() {
+		return t.Value
+	}
+	return float64(t.Count)
+}
+
+// Day returns the local calendar day (DayLayout) the completion counts toward,
+// deriving it from CompletedAt when LocalDate is unset.
+func (c Completion) Day() string {
+	if c.LocalDate != "" {
+		return c.LocalDate
+	}
+	return CompletionDay(c.CompletedAt)
+}
+
+// CompletionDay converts an RFC3339 timestamp to the DayLayout key of the
+// day it counts toward (see HabitDay), or "" if it can't be parsed.
+func CompletionDay(completedAt string) string {
+	t, err := time.Parse(time.RFC3339, completedAt)
+	if err != nil {
+		return ""
+	}
+	return DayKey(HabitDay(t))
+}
+
+// EffectiveGoal clamps unset or invalid goals to one completion per day.
+func EffectiveGoal(goal int) int {
+	if goal < 1 {
+		return 1
+	}
+	return goal
+}
+
+// Label is the habit's name, prefixed with its icon when it has one.
+func (h Habit) Label() string {
+	if h.Icon != "" {
+		return h.Icon + " " + h.Name
+	}
+	return h.Name
+}
+
+// IsQuantity reports whether the habit tracks an amount rather than check-ins.
+func (h Habit) IsQuantity() bool {
+	return h.Kind == HabitKindQuantity
+}
+
+// DailyTarget is the amount a day needs to count as done: Target for
+// quantity habits, the check-in goal otherwise.
+func (h Habit) DailyTarget() float64 {
+	if h.IsQuantity() {
+		if h.Target > 0 {
+			return h.Target
+		}
+		return 1
+	}
+	return float64(EffectiveGoal(h.Goal))
+}
+
+// CompletionAmount is how much c contributes toward the habit's daily target.
+func (h Habit) CompletionAmount(c Completion) float64 {
+	if h.IsQuantity() {
+		return c.Value
+	}
+	return 1
+}
+
+// TargetMet reports whether amount reaches the daily target, tolerating
+// float rounding from summed decimal entries.
+func (h Habit) TargetMet(amount float64) bool {
+	return amount >= h.DailyTarget()-1e-9
+}
+
+// FormatProgress renders a day's progress as "1/2" or "18/30 pages".
+func (h Habit) FormatProgress(amount float64) string {
+	progress := fmt.Sprintf("%s/%s", FormatAmount(amount), FormatAmount(h.DailyTarget()))
+	if h.IsQuantity() && h.Unit != "" {
+		progress += " " + h.Unit
+	}
+	return progress
+}
+
+// FormatAmount prints whole numbers without decimals and others with at most two.
+func FormatAmount(v float64) string {
+	return strconv.FormatFloat(math.Round(v*100)/100, 'f', -1, 64)
+}
+
+// HabitColors lists the theme color slots a habit can use. These follow the
+// active theme; hex and 256-color values stay fixed.
+var HabitColors = []string{"red", "blue", "green", "yellow", "orange", "purple", "pink"}
+
+// IsThemeColor reports whether color names a theme slot rather than a fixed color.
+func IsThemeColor(color string) bool {
+	for _, c := range HabitColors {
+		if c == color {
+			return true
+		}
+	}
+	return false
+}
+
+// NormalizeColor lower-cases a habit color and expands #rgb to #rrggbb. It
+// accepts HabitColors names, hex values and 256-color indexes 0-255.
+func NormalizeColor(color string) (string, error) {
+	c := strings.ToLower(strings.TrimSpace(color))
+	if IsThemeColor(c) {
+		return c, nil
+	}
+	if hex, ok := strings.CutPrefix(c, "#"); ok && (len(hex) == 3 || len(hex) == 6) {
+		if _, err := strconv.ParseUint(hex, 16, 32); err == nil {
+			if len(hex) == 3 {
+				hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+			}
+			return "#" + hex, nil
+		}
+	}
+	if n, err := strconv.Atoi(c); err == nil && n >= 0 && n <= 255 {
+		return strconv.Itoa(n), nil
+	}
+	return "", fmt.Errorf("invalid color %q (use %s, a hex value like #1e90ff, or 0-255)", color, strings.Join(HabitColors, ", "))
+}
+
+// MaxIconWidth is the most terminal columns an icon may take, so habit names
+// stay aligned in fixed-width columns.
+const MaxIconWidth = 2
+
+// NormalizeIcon trims an icon and checks it is one short glyph: an emoji or a
+// symbol one or two columns wide. An empty icon means none.
+func NormalizeIcon(icon string) (string, error) {
+	icon = strings.TrimSpace(icon)
+	if icon == "" {
+		return "", nil
+	}
+	for _, r := range icon {
+		if unicode.IsSpace(r) || unicode.IsControl(r) {
+			return "", fmt.Errorf("invalid icon %q: must be a single emoji or glyph", icon)
+		}
+	}
+	if w := runewidth.StringWidth(icon); w < 1 || w > MaxIconWidth {
+		return "", fmt.Errorf("invalid icon %q: must be at most %d columns wide", icon, MaxIconWidth)
+	}
+	return icon, nil
+}
+
+// Weekdays lists the day names accepted in Frequency, in calendar order.
+var Weekdays = []string{
+	"monday", "tuesday", "wednesday", "thursday", "friday", "saturday", "sunday",
+}
+
+// NormalizeFrequency stores empty or all-days schedules as "daily".
+func NormalizeFrequency(days []string) string {
+	cleaned := make([]string, 0, len(days))
+	seen := make(map[string]bool, len(days))
+	for _, d := range days {
+		d = strings.ToLower(strings.TrimSpace(d))
+		if d == "" || d == "daily" || seen[d] {
+			continue
+		}
+		seen[d] = true
+		cleaned = append(cleaned, d)
+	}
+	if len(cleaned) == 0 || len(cleaned) == 7 {
+		return "daily"
+	}
+	return strings.Join(cleaned, ",")
+}
+
+// ParseFrequency splits a comma-separated weekday list into a lookup set.
+func ParseFrequency(frequency string) map[string]bool {
+	days := make(map[string]bool)
+	for _, d := range strings.Split(strings.ToLower(frequency), ",") {
+		d = strings.TrimSpace(d)
+		if d != "" {
+			days[d] = true
+		}
+	}
+	return days
+}
+
+// DayName returns the lowercase weekday name used in Frequency.
+func DayName(t time.Time) string {
+	return strings.ToLower(t.Weekday().String())
+}
+
+// IsScheduledOnDay reports whether frequency includes dayName; empty and "daily" match every day.
+func IsScheduledOnDay(frequency string, dayName string) bool {
+	if frequency == "" || strings.ToLower(frequency) == "daily" {
+		return true
+	}
+	days := ParseFrequency(frequency)
+	return days[dayName]
+}
+
+// CompletionTimeOnDay places the wall-clock time of now on day, so back-filled
+// check-ins keep a realistic time of day. Times before the day rollover go on
+// the following date, which still counts toward day.
+func CompletionTimeOnDay(day, now time.Time) time.Time {
+	t := time.Date(
+		day.Year(), day.Month(), day.Day(),
+		now.Hour(), now.Minute(), now.Second(), 0, now.Location(),
+	)
+	if beforeRollover(now) {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t
 }
@@ -0,0 +1,60 @@
+package models
+
+import "testing"
+
+func TestNormalizeColor(t *testing.T) {
+	tests := []struct {
+		in, want string
+		ok       bool
+	}{
+		{in: " Blue ", want: "blue", ok: true},
+		{in: "#1E90FF", want: "#1e90ff", ok: true},
+		{in: "#abc", want: "#aabbcc", ok: true},
+		{in: "208", want: "208", ok: true},
+		{in: "0", want: "0", ok: true},
+		{in: "256", ok: false},
+		{in: "#12345", ok: false},
+		{in: "#ggg", ok: false},
+		{in: "teal", ok: false},
+		{in: "", ok: false},
+	}
+	for _, tt := range tests {
+		got, err := NormalizeColor(tt.in)
+		if (err == nil) != tt.ok || got != tt.want {
+			t.Fatalf("NormalizeColor(%q) = %q, %v; want %q, ok=%v", tt.in, got, err, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestNormalizeIcon(t *testing.T) {
+	tests := []struct {
+		in, want string
+		ok       bool
+	}{
+		{in: "", want: "", ok: true},
+		{in: " 🏃 ", want: "🏃", ok: true},
+		{in: "❤️", want: "❤️", ok: true},
+		{in: "*", want: "*", ok: true},
+		{in: "\uf02d", want: "\uf02d", ok: true},
+		{in: "🏃🏃", ok: false},
+		{in: "abc", ok: false},
+		{in: "a b", ok: false},
+		{in: "\t", want: "", ok: true},
+		{in: "\u200b", ok: false},
+	}
+	for _, tt := range tests {
+		got, err := NormalizeIcon(tt.in)
+		if (err == nil) != tt.ok || got != tt.want {
+			t.Fatalf("NormalizeIcon(%q) = %q, %v; want %q, ok=%v", tt.in, got, err, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestHabitLabel(t *testing.T) {
+	if got := (Habit{Name: "Run"}).Label(); got != "Run" {
+		t.Fatalf("Label() = %q, want Run", got)
+	}
+	if got := (Habit{Name: "Run", Icon: "🏃"}).Label(); got != "🏃 Run" {
+		t.Fatalf("Label() = %q, want the icon before the name", got)
+	}
+}
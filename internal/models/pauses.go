@@ -0,0 +1,107 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// DayLayout is the format of calendar-day keys such as Skip.Day.
+const DayLayout = "2006-01-02"
+
+// DayKey formats t's local calendar day as a DayLayout key.
+func DayKey(t time.Time) string {
+	return localDay(t).Format(DayLayout)
+}
+
+// Skip excuses a habit (or, with HabitID 0, every habit) on one day.
+type Skip struct {
+	ID      int64
+	HabitID int64  // 0 skips every habit
+	Day     string // DayLayout, local calendar day
+}
+
+// Vacation pauses every habit from StartDay through EndDay inclusive.
+type Vacation struct {
+	ID       int64
+	StartDay string // DayLayout
+	EndDay   string // DayLayout
+	Note     string
+}
+
+// Validate checks the day format and that the range is not reversed.
+func (v Vacation) Validate() error {
+	start, err := time.Parse(DayLayout, v.StartDay)
+	if err != nil {
+		return fmt.Errorf("invalid start day %q", v.StartDay)
+	}
+	end, err := time.Parse(DayLayout, v.EndDay)
+	if err != nil {
+		return fmt.Errorf("invalid end day %q", v.EndDay)
+	}
+	if end.Before(start) {
+		return fmt.Errorf("vacation ends (%s) before it starts (%s)", v.EndDay, v.StartDay)
+	}
+	return nil
+}
+
+// Days counts the days the vacation covers.
+func (v Vacation) Days() int {
+	start, err1 := time.Parse(DayLayout, v.StartDay)
+	end, err2 := time.Parse(DayLayout, v.EndDay)
+	if err1 != nil || err2 != nil {
+		return 0
+	}
+	return int(end.Sub(start).Hours()/24) + 1
+}
+
+// Pauses answers whether a habit is excused on a day. Paused days are not
+// required: they neither count toward nor break streaks and rates. The zero
+// value pauses nothing.
+type Pauses struct {
+	skips     map[string]map[int64]bool
+	vacations []Vacation
+}
+
+// NewPauses indexes skips and vacations for lookup.
+func NewPauses(skips []Skip, vacations []Vacation) Pauses {
+	p := Pauses{skips: make(map[string]map[int64]bool), vacations: vacations}
+	for _, s := range skips {
+		if p.skips[s.Day] == nil {
+			p.skips[s.Day] = make(map[int64]bool)
+		}
+		p.skips[s.Day][s.HabitID] = true
+	}
+	return p
+}
+
+// IsSkipped reports whether habitID was skipped on day, individually or as
+// part of an all-habits skip.
+func (p Pauses) IsSkipped(habitID int64, day time.Time) bool {
+	byHabit := p.skips[DayKey(day)]
+	return byHabit[habitID] || byHabit[0]
+}
+
+// SkippedForHabit reports whether habitID has its own skip on day.
+func (p Pauses) SkippedForHabit(habitID int64, day time.Time) bool {
+	return p.skips[DayKey(day)][habitID]
+}
+
+// VacationOn returns the vacation covering day, if any.
+func (p Pauses) VacationOn(day time.Time) (Vacation, bool) {
+	key := DayKey(day)
+	for _, v := range p.vacations {
+		if v.StartDay <= key && key <= v.EndDay {
+			return v, true
+		}
+	}
+	return Vacation{}, false
+}
+
+// IsPaused reports whether habitID is excused on day by a skip or a vacation.
+func (p Pauses) IsPaused(habitID int64, day time.Time) bool {
+	if p.IsSkipped(habitID, day) {
+		return true
+	}
+	_, ok := p.VacationOn(day)
+	return ok
+}
@@ -0,0 +1,199 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	RecurrenceWeekdays = "weekdays" // the weekdays listed in Habit.Frequency
+	RecurrenceInterval = "interval" // every Interval days counted from StartDate
+	RecurrenceWeekly   = "weekly"   // TimesPerWeek days of the habit's choosing
+	RecurrenceMonthly  = "monthly"  // the listed days of each month
+)
+
+// Recurrence describes schedules that a weekday list cannot express.
+// The zero value means "use Habit.Frequency".
+type Recurrence struct {
+	Kind         string `json:"kind"`
+	Interval     int    `json:"interval,omitempty"`
+	TimesPerWeek int    `json:"times_per_week,omitempty"`
+	MonthDays    []int  `json:"month_days,omitempty"` // 1-31; short months use their last day
+}
+
+// IsWeekdays reports whether the schedule comes from Habit.Frequency.
+func (r Recurrence) IsWeekdays() bool {
+	return r.Kind == "" || r.Kind == RecurrenceWeekdays
+}
+
+// IsWeeklyQuota reports whether the habit is due a number of times per week on any days.
+func (r Recurrence) IsWeeklyQuota() bool {
+	return r.Kind == RecurrenceWeekly
+}
+
+// Normalize validates r and returns it in canonical form; weekday schedules collapse to the zero value.
+func (r Recurrence) Normalize() (Recurrence, error) {
+	switch r.Kind {
+	case "", RecurrenceWeekdays:
+		return Recurrence{}, nil
+	case RecurrenceInterval:
+		if r.Interval < 1 {
+			return Recurrence{}, errors.New("interval must be at least 1 day")
+		}
+		if r.Interval == 1 {
+			return Recurrence{}, nil
+		}
+		return Recurrence{Kind: RecurrenceInterval, Interval: r.Interval}, nil
+	case RecurrenceWeekly:
+		if r.TimesPerWeek < 1 || r.TimesPerWeek > 7 {
+			return Recurrence{}, errors.New("times per week must be between 1 and 7")
+		}
+		return Recurrence{Kind: RecurrenceWeekly, TimesPerWeek: r.TimesPerWeek}, nil
+	case RecurrenceMonthly:
+		seen := make(map[int]bool, len(r.MonthDays))
+		days := make([]int, 0, len(r.MonthDays))
+		for _, d := range r.MonthDays {
+			if d < 1 || d > 31 {
+				return Recurrence{}, fmt.Errorf("day of month %d is out of range 1-31", d)
+			}
+			if !seen[d] {
+				seen[d] = true
+				days = append(days, d)
+			}
+		}
+		if len(days) == 0 {
+			return Recurrence{}, errors.New("pick at least one day of the month")
+		}
+		sort.Ints(days)
+		return Recurrence{Kind: RecurrenceMonthly, MonthDays: days}, nil
+	default:
+		return Recurrence{}, fmt.Errorf("unknown recurrence %q", r.Kind)
+	}
+}
+
+// Encode serializes r for storage; weekday schedules encode as "".
+func (r Recurrence) Encode() string {
+	if r.IsWeekdays() {
+		return ""
+	}
+	data, err := json.Marshal(r)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// ParseRecurrence decodes a stored recurrence; "" yields the weekday schedule.
+func ParseRecurrence(s string) (Recurrence, error) {
+	if strings.TrimSpace(s) == "" {
+		return Recurrence{}, nil
+	}
+	var r Recurrence
+	if err := json.Unmarshal([]byte(s), &r); err != nil {
+		return Recurrence{}, err
+	}
+	return r.Normalize()
+}
+
+// ParseMonthDays reads a list such as "1, 15" into day numbers.
+func ParseMonthDays(s string) ([]int, error) {
+	var days []int
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		d, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a day of the month", part)
+		}
+		days = append(days, d)
+	}
+	return days, nil
+}
+
+// FormatMonthDays is the inverse of ParseMonthDays.
+func FormatMonthDays(days []int) string {
+	parts := make([]string, len(days))
+	for i, d := range days {
+		parts[i] = strconv.Itoa(d)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func localDay(t time.Time) time.Time {
+	t = t.In(time.Local)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+}
+
+// daysBetween counts calendar days from a to b, ignoring DST hour shifts.
+func daysBetween(a, b time.Time) int {
+	a, b = localDay(a), localDay(b)
+	ua := time.Date(a.Year(), a.Month(), a.Day(), 0, 0, 0, 0, time.UTC)
+	ub := time.Date(b.Year(), b.Month(), b.Day(), 0, 0, 0, 0, time.UTC)
+	return int(ub.Sub(ua).Hours() / 24)
+}
+
+// ScheduledOn reports whether day is a due day for the habit. Weekly-quota
+// habits may be done on any day, so every day is eligible.
+func (h Habit) ScheduledOn(day time.Time) bool {
+	switch h.Recurrence.Kind {
+	case RecurrenceInterval:
+		anchor, err := time.Parse(time.RFC3339, h.StartDate)
+		if err != nil {
+			return true
+		}
+		n := daysBetween(HabitDay(anchor), day)
+		return n >= 0 && n%h.Recurrence.Interval == 0
+	case RecurrenceWeekly:
+		return true
+	case RecurrenceMonthly:
+		day = localDay(day)
+		lastDay := time.Date(day.Year(), day.Month()+1, 0, 0, 0, 0, 0, time.Local).Day()
+		for _, d := range h.Recurrence.MonthDays {
+			if d > lastDay {
+				d = lastDay
+			}
+			if d == day.Day() {
+				return true
+			}
+		}
+		return false
+	default:
+		return IsScheduledOnDay(h.Frequency, DayName(day))
+	}
+}
+
+var shortDayNames = map[string]string{
+	"monday": "Mon", "tuesday": "Tue", "wednesday": "Wed", "thursday": "Thu",
+	"friday": "Fri", "saturday": "Sat", "sunday": "Sun",
+}
+
+// ScheduleLabel describes the habit's schedule for display, e.g. "Mon, Wed" or "3× per week".
+func (h Habit) ScheduleLabel() string {
+	switch h.Recurrence.Kind {
+	case RecurrenceInterval:
+		return fmt.Sprintf("Every %d days", h.Recurrence.Interval)
+	case RecurrenceWeekly:
+		return fmt.Sprintf("%d× per week", h.Recurrence.TimesPerWeek)
+	case RecurrenceMonthly:
+		return "Monthly on " + FormatMonthDays(h.Recurrence.MonthDays)
+	}
+	freq := strings.ToLower(strings.TrimSpace(h.Frequency))
+	if freq == "" || freq == "daily" {
+		return "Daily"
+	}
+	days := ParseFrequency(freq)
+	var names []string
+	for _, d := range Weekdays {
+		if days[d] {
+			names = append(names, shortDayNames[d])
+		}
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestScheduledOnInterval(t *testing.T) {
+	loc := time.Local
+	h := Habit{
+		StartDate:  time.Date(2026, 7, 1, 18, 0, 0, 0, loc).Format(time.RFC3339),
+		Recurrence: Recurrence{Kind: RecurrenceInterval, Interval: 2},
+	}
+	for day, want := range map[int]bool{1: true, 2: false, 3: true, 30: false, 31: true} {
+		if got := h.ScheduledOn(time.Date(2026, 7, day, 9, 0, 0, 0, loc)); got != want {
+			t.Fatalf("every-2-days on Jul %d = %v, want %v", day, got, want)
+		}
+	}
+	if h.ScheduledOn(time.Date(2026, 6, 29, 9, 0, 0, 0, loc)) {
+		t.Fatal("interval should not schedule days before the start date")
+	}
+}
+
+func TestScheduledOnMonthlyClampsShortMonths(t *testing.T) {
+	loc := time.Local
+	h := Habit{Recurrence: Recurrence{Kind: RecurrenceMonthly, MonthDays: []int{1, 31}}}
+	if !h.ScheduledOn(time.Date(2026, 2, 28, 9, 0, 0, 0, loc)) {
+		t.Fatal("31st should fall back to Feb 28")
+	}
+	if h.ScheduledOn(time.Date(2026, 3, 30, 9, 0, 0, 0, loc)) {
+		t.Fatal("Mar 30 should not be scheduled")
+	}
+	if !h.ScheduledOn(time.Date(2026, 3, 1, 9, 0, 0, 0, loc)) {
+		t.Fatal("Mar 1 should be scheduled")
+	}
+}
+
+func TestRecurrenceRoundTrip(t *testing.T) {
+	r, err := Recurrence{Kind: RecurrenceMonthly, MonthDays: []int{15, 1, 15}}.Normalize()
+	if err != nil {
+		t.Fatalf("normalize: %v", err)
+	}
+	parsed, err := ParseRecurrence(r.Encode())
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if parsed.Kind != RecurrenceMonthly || len(parsed.MonthDays) != 2 || parsed.MonthDays[0] != 1 {
+		t.Fatalf("round-trip = %+v", parsed)
+	}
+	if (Recurrence{Kind: RecurrenceWeekdays}).Encode() != "" {
+		t.Fatal("weekday recurrence should encode as empty string")
+	}
+	if _, err := (Recurrence{Kind: RecurrenceWeekly, TimesPerWeek: 8}).Normalize(); err == nil {
+		t.Fatal("expected error for 8 times per week")
+	}
+}
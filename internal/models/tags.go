@@ -0,0 +1,52 @@
+package models
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// NormalizeTag lowercases a tag, drops a leading '#' and turns inner spaces
+// into dashes; it returns "" for tags with nothing left.
+func NormalizeTag(tag string) string {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	tag = strings.TrimLeft(tag, "#")
+	return strings.Join(strings.FieldsFunc(tag, unicode.IsSpace), "-")
+}
+
+// NormalizeTags normalizes, dedupes and sorts tags.
+func NormalizeTags(tags []string) []string {
+	seen := make(map[string]bool, len(tags))
+	var out []string
+	for _, t := range tags {
+		t = NormalizeTag(t)
+		if t == "" || seen[t] {
+			continue
+		}
+		seen[t] = true
+		out = append(out, t)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// ParseTags reads a comma-separated list such as "health, work".
+func ParseTags(s string) []string {
+	return NormalizeTags(strings.Split(s, ","))
+}
+
+// FormatTags is the inverse of ParseTags.
+func FormatTags(tags []string) string {
+	return strings.Join(tags, ", ")
+}
+
+// HasTag reports whether the habit carries tag.
+func (h Habit) HasTag(tag string) bool {
+	tag = NormalizeTag(tag)
+	for _, t := range h.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,14 @@
+package models
+
+import "testing"
+
+func TestParseTags(t *testing.T) {
+	got := ParseTags(" Health, #work ,, deep  work, health")
+	if want := "deep-work, health, work"; FormatTags(got) != want {
+		t.Fatalf("ParseTags = %q, want %q", FormatTags(got), want)
+	}
+	h := Habit{Tags: got}
+	if !h.HasTag("#Work") || h.HasTag("learning") {
+		t.Fatalf("HasTag mismatch for %v", h.Tags)
+	}
+}
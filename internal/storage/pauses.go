@@ -0,0 +1,120 @@
+package storage
+
+import (
+	"context"
+	"errors"
+
+	"github.com/bShaak/habitui/internal/models"
+)
+
+// CreateSkip marks a habit (or every habit, for HabitID 0) as skipped on
+// s.Day. Skipping a day twice is a no-op.
+func (s *SQLiteStore) CreateSkip(ctx context.Context, skip *models.Skip) (*models.Skip, error) {
+	if skip == nil || skip.Day == "" {
+		return nil, errors.New("invalid skip")
+	}
+	if _, err := s.conn().ExecContext(ctx, `
+		INSERT INTO skips(habit_id, day) VALUES(?, ?)
+		ON CONFLICT(habit_id, day) DO NOTHING`,
+		skip.HabitID, skip.Day); err != nil {
+		return nil, err
+	}
+	if err := s.conn().QueryRowContext(ctx, `
+		SELECT id FROM skips WHERE habit_id = ? AND day = ?`,
+		skip.HabitID, skip.Day).Scan(&skip.ID); err != nil {
+		return nil, err
+	}
+	return skip, nil
+}
+
+// DeleteSkip removes the skip for habitID (0 for all habits) on day.
+func (s *SQLiteStore) DeleteSkip(ctx context.Context, habitID int64, day string) error {
+	_, err := s.conn().ExecContext(ctx, `DELETE FROM skips WHERE habit_id = ? AND day = ?`, habitID, day)
+	return err
+}
+
+func (s *SQLiteStore) ListSkips(ctx context.Context) ([]models.Skip, error) {
+	rows, err := s.conn().QueryContext(ctx, `
+		SELECT id, habit_id, day
+		FROM skips
+		ORDER BY day ASC, habit_id ASC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []models.Skip
+	for rows.Next() {
+		var sk models.Skip
+		if err := rows.Scan(&sk.ID, &sk.HabitID, &sk.Day); err != nil {
+			return nil, err
+		}
+		out = append(out, sk)
+	}
+	return out, rows.Err()
+}
+
+func (s *SQLiteStore) CreateVacation(ctx context.Context, v *models.Vacation) (*models.Vacation, error) {
+	if v == nil {
+		return nil, errors.New("vacation is nil")
+	}
+	if err := v.Validate(); err != nil {
+		return nil, err
+	}
+	res, err := s.conn().ExecContext(ctx, `
+		INSERT INTO vacations(start_day, end_day, note)
+		VALUES(?, ?, ?)`,
+		v.StartDay, v.EndDay, v.Note)
+	if err != nil {
+		return nil, err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+	v.ID = id
+	return v, nil
+}
+
+func (s *SQLiteStore) DeleteVacation(ctx context.Context, id int64) error {
+	if id == 0 {
+		return errors.New("invalid id")
+	}
+	_, err := s.conn().ExecContext(ctx, `DELETE FROM vacations WHERE id = ?`, id)
+	return err
+}
+
+// ListVacations returns vacations, most recent first.
+func (s *SQLiteStore) ListVacations(ctx context.Context) ([]models.Vacation, error) {
+	rows, err := s.conn().QueryContext(ctx, `
+		SELECT id, start_day, end_day, note
+		FROM vacations
+		ORDER BY start_day DESC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []models.Vacation
+	for rows.Next() {
+		var v models.Vacation
+		if err := rows.Scan(&v.ID, &v.StartDay, &v.EndDay, &v.Note); err != nil {
+			return nil, err
+		}
+		out = append(out, v)
+	}
+	return out, rows.Err()
+}
+
+// LoadPauses reads every skip and vacation into a lookup.
+func LoadPauses(ctx context.Context, store Store) (models.Pauses, error) {
+	skips, err := store.ListSkips(ctx)
+	if err != nil {
+		return models.Pauses{}, err
+	}
+	vacations, err := store.ListVacations(ctx)
+	if err != nil {
+		return models.Pauses{}, err
+	}
+	return models.NewPauses(skips, vacations), nil
+}
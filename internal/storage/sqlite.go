@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
@@ -15,6 +16,47 @@ import (
 
 type SQLiteStore struct {
 	db *sql.DB
+	tx *sql.Tx // set on the store InTx hands to its callback
+}
+
+// querier is what *sql.DB and *sql.Tx have in common.
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// conn is the transaction InTx opened, or the database outside of one.
+func (s *SQLiteStore) conn() querier {
+	if s.tx != nil {
+		return s.tx
+	}
+	return s.db
+}
+
+// InTx runs fn against a store whose writes all commit when fn returns nil
+// and are rolled back otherwise. Calls nested inside fn join the transaction.
+func (s *SQLiteStore) InTx(ctx context.Context, fn func(Store) error) error {
+	return s.withTx(ctx, func(tx *sql.Tx) error {
+		return fn(&SQLiteStore{db: s.db, tx: tx})
+	})
+}
+
+// withTx runs fn in a transaction, or in the one InTx opened.
+func (s *SQLiteStore) withTx(ctx context.Context, fn func(*sql.Tx) error) error {
+	if s.tx != nil {
+		return fn(s.tx)
+	}
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
 }
 
 func getDBPath() string {
@@ -26,11 +68,15 @@ func getDBPath() string {
 	return filepath.Join(dir, "habit.db")
 }
 
-func OpenSQLite() (*SQLiteStore, error) {
-	return OpenSQLiteAt(getDBPath())
+// OpenSQLite opens the database in ~/.habitui. rollover is the configured
+// day_ends_at; nil, when the config could not be read, keeps the rollover the
+// stored days were computed with.
+func OpenSQLite(rollover *time.Duration) (*SQLiteStore, error) {
+	return OpenSQLiteAt(getDBPath(), rollover)
 }
 
-func OpenSQLiteAt(dbPath string) (*SQLiteStore, error) {
+// OpenSQLiteAt is OpenSQLite for the database at dbPath.
+func OpenSQLiteAt(dbPath string, rollover *time.Duration) (*SQLiteStore, error) {
 	if err := os.MkdirAll(filepath.Dir(dbPath), 0o755); err != nil {
 		return nil, err
 	}
@@ -47,7 +93,7 @@ func OpenSQLiteAt(dbPath string) (*SQLiteStore, error) {
 		return nil, err
 	}
 	store := &SQLiteStore{db: db}
-	if err := store.migrate(); err != nil {
+	if err := store.migrate(rollover); err != nil {
 		_ = db.Close()
 		return nil, err
 	}
@@ -58,7 +104,7 @@ func isDuplicateColumnErr(err error) bool {
 	return err != nil && strings.Contains(strings.ToLower(err.Error()), "duplicate column name")
 }
 
-func (s *SQLiteStore) migrate() error {
+func (s *SQLiteStore) migrate(rollover *time.Duration) error {
 	if _, err := s.db.Exec(`
 		CREATE TABLE IF NOT EXISTS schema_migrations (
 			version INTEGER PRIMARY KEY
@@ -76,6 +122,13 @@ func (s *SQLiteStore) migrate() error {
 		s.migrateV1,
 		s.migrateV2,
 		s.migrateV3,
+		s.migrateV4,
+		s.migrateV5,
+		s.migrateV6,
+		s.migrateV7,
+		s.migrateV8,
+		s.migrateV9,
+		s.migrateV10,
 	}
 	for i, fn := range migrations {
 		v := i + 1
@@ -89,7 +142,7 @@ func (s *SQLiteStore) migrate() error {
 			return err
 		}
 	}
-	return nil
+	return s.syncDayRollover(rollover)
 }
 
 func (s *SQLiteStore) migrateV1() error {
@@ -148,9 +201,205 @@ func (s *SQLiteStore) migrateV3() error {
 	return nil
 }
 
+// migrateV4 adds quantity habits: a kind, unit and daily target per habit
+// and a logged value per completion (1 for plain check-ins).
+func (s *SQLiteStore) migrateV4() error {
+	for _, stmt := range []string{
+		`ALTER TABLE habits ADD COLUMN kind TEXT NOT NULL DEFAULT 'count'`,
+		`ALTER TABLE habits ADD COLUMN unit TEXT NOT NULL DEFAULT ''`,
+		`ALTER TABLE habits ADD COLUMN target REAL NOT NULL DEFAULT 0`,
+		`ALTER TABLE completions ADD COLUMN value REAL NOT NULL DEFAULT 1`,
+	} {
+		if _, err := s.db.Exec(stmt); err != nil && !isDuplicateColumnErr(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// migrateV5 stores structured recurrences (JSON) next to the weekday frequency.
+func (s *SQLiteStore) migrateV5() error {
+	_, err := s.db.Exec(`ALTER TABLE habits ADD COLUMN recurrence TEXT NOT NULL DEFAULT ''`)
+	if err != nil && !isDuplicateColumnErr(err) {
+		return err
+	}
+	return nil
+}
+
+// migrateV6 adds a manual sort position, seeded from the previous created_at order.
+func (s *SQLiteStore) migrateV6() error {
+	_, err := s.db.Exec(`ALTER TABLE habits ADD COLUMN sort_order INTEGER NOT NULL DEFAULT 0`)
+	if err != nil && !isDuplicateColumnErr(err) {
+		return err
+	}
+	_, err = s.db.Exec(`
+		UPDATE habits SET sort_order = (
+			SELECT COUNT(*) FROM habits AS h
+			WHERE h.created_at < habits.created_at
+				OR (h.created_at = habits.created_at AND h.id <= habits.id)
+		)`)
+	return err
+}
+
+// migrateV7 adds many-to-many habit tags.
+func (s *SQLiteStore) migrateV7() error {
+	if _, err := s.db.Exec(`
+		CREATE TABLE IF NOT EXISTS tags (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL UNIQUE
+		);
+	`); err != nil {
+		return err
+	}
+	_, err := s.db.Exec(`
+		CREATE TABLE IF NOT EXISTS habit_tags (
+			habit_id INTEGER NOT NULL,
+			tag_id INTEGER NOT NULL,
+			PRIMARY KEY (habit_id, tag_id),
+			FOREIGN KEY (habit_id) REFERENCES habits (id) ON DELETE CASCADE,
+			FOREIGN KEY (tag_id) REFERENCES tags (id) ON DELETE CASCADE
+		);
+	`)
+	return err
+}
+
+// migrateV8 adds skipped days (per habit, or habit_id 0 for all habits) and
+// vacation ranges that pause every schedule.
+func (s *SQLiteStore) migrateV8() error {
+	if _, err := s.db.Exec(`
+		CREATE TABLE IF NOT EXISTS skips (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			habit_id INTEGER NOT NULL DEFAULT 0,
+			day TEXT NOT NULL,
+			UNIQUE (habit_id, day)
+		);
+	`); err != nil {
+		return err
+	}
+	_, err := s.db.Exec(`
+		CREATE TABLE IF NOT EXISTS vacations (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			start_day TEXT NOT NULL,
+			end_day TEXT NOT NULL,
+			note TEXT NOT NULL DEFAULT ''
+		);
+	`)
+	return err
+}
+
+// migrateV9 stores each completion's local calendar day so day and range
+// lookups are exact indexed queries instead of padded timestamp scans.
+func (s *SQLiteStore) migrateV9() error {
+	_, err := s.db.Exec(`ALTER TABLE completions ADD COLUMN local_date TEXT NOT NULL DEFAULT ''`)
+	if err != nil && !isDuplicateColumnErr(err) {
+		return err
+	}
+	if err := s.rekeyLocalDates(`WHERE local_date = ''`, nil); err != nil {
+		return err
+	}
+	_, err = s.db.Exec(`CREATE INDEX IF NOT EXISTS idx_completions_local_date ON completions (local_date, habit_id)`)
+	return err
+}
+
+// migrateV10 adds a key/value table for state the store derives data from,
+// such as the day rollover local_date was computed with.
+func (s *SQLiteStore) migrateV10() error {
+	_, err := s.db.Exec(`
+		CREATE TABLE IF NOT EXISTS settings (
+			key TEXT PRIMARY KEY,
+			value TEXT NOT NULL
+		);
+	`)
+	return err
+}
+
+// syncDayRollover re-keys every completion's local_date when the configured
+// day rollover differs from the one the stored days were computed with, so
+// changing day_ends_at moves past late-night check-ins too. Either way the
+// rollover in effect becomes models.DayRollover; without a configured value
+// (nil) the stored one is adopted.
+func (s *SQLiteStore) syncDayRollover(rollover *time.Duration) error {
+	var have string
+	err := s.db.QueryRow(`SELECT value FROM settings WHERE key = 'day_rollover'`).Scan(&have)
+	if errors.Is(err, sql.ErrNoRows) {
+		have = time.Duration(0).String()
+	} else if err != nil {
+		return err
+	}
+	if rollover == nil {
+		stored, err := time.ParseDuration(have)
+		if err != nil {
+			return fmt.Errorf("invalid stored day_rollover %q: %w", have, err)
+		}
+		models.SetDayRollover(stored)
+		return nil
+	}
+	models.SetDayRollover(*rollover)
+	want := rollover.String()
+	if have == want {
+		return nil
+	}
+	return s.rekeyLocalDates("", func(tx *sql.Tx) error {
+		_, err := tx.Exec(`
+			INSERT INTO settings(key, value) VALUES('day_rollover', ?)
+			ON CONFLICT(key) DO UPDATE SET value = excluded.value`, want)
+		return err
+	})
+}
+
+// rekeyLocalDates derives local_date from completed_at in Go for the
+// completions matched by where, since SQLite's date functions don't know the
+// local time zone or the day rollover. finish, if set, runs in the same
+// transaction.
+func (s *SQLiteStore) rekeyLocalDates(where string, finish func(*sql.Tx) error) error {
+	rows, err := s.db.Query(`SELECT id, completed_at, local_date FROM completions ` + where)
+	if err != nil {
+		return err
+	}
+	days := make(map[int64]string)
+	for rows.Next() {
+		var (
+			id                     int64
+			completedAt, localDate string
+		)
+		if err := rows.Scan(&id, &completedAt, &localDate); err != nil {
+			rows.Close()
+			return err
+		}
+		if day := models.CompletionDay(completedAt); day != localDate {
+			days[id] = day
+		}
+	}
+	if err := rows.Close(); err != nil {
+		return err
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	stmt, err := tx.Prepare(`UPDATE completions SET local_date = ? WHERE id = ?`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	for id, day := range days {
+		if _, err := stmt.Exec(day, id); err != nil {
+			return err
+		}
+	}
+	if finish != nil {
+		if err := finish(tx); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
 func (s *SQLiteStore) Close() error { return s.db.Close() }
 
-func normalizeHabitDefaults(h *models.Habit) {
+func normalizeHabitDefaults(h *models.Habit) error {
 	if h.Frequency == "" {
 		h.Frequency = "daily"
 	}
@@ -160,13 +409,42 @@ func normalizeHabitDefaults(h *models.Habit) {
 	if h.Color == "" {
 		h.Color = "red"
 	}
+	color, err := models.NormalizeColor(h.Color)
+	if err != nil {
+		return err
+	}
+	h.Color = color
+	icon, err := models.NormalizeIcon(h.Icon)
+	if err != nil {
+		return err
+	}
+	h.Icon = icon
+	h.Tags = models.NormalizeTags(h.Tags)
+	if h.Kind != models.HabitKindQuantity {
+		h.Kind = models.HabitKindCount
+		h.Unit = ""
+		h.Target = 0
+	} else if h.Target <= 0 {
+		h.Target = 1
+	}
+	recurrence, err := h.Recurrence.Normalize()
+	if err != nil {
+		return err
+	}
+	h.Recurrence = recurrence
+	if !recurrence.IsWeekdays() {
+		h.Frequency = "daily"
+	}
+	return nil
 }
 
 func (s *SQLiteStore) CreateHabit(ctx context.Context, h *models.Habit) (*models.Habit, error) {
 	if h == nil {
 		return nil, errors.New("habit is nil")
 	}
-	normalizeHabitDefaults(h)
+	if err := normalizeHabitDefaults(h); err != nil {
+		return nil, err
+	}
 	now := time.Now()
 	if h.StartDate == "" {
 		h.StartDate = now.Format(time.RFC3339)
@@ -179,17 +457,35 @@ func (s *SQLiteStore) CreateHabit(ctx context.Context, h *models.Habit) (*models
 			h.StartDate = now.Format(time.RFC3339)
 		}
 	}
-	h.CreatedAt = now.Format(time.RFC3339)
-	h.UpdatedAt = now.Format(time.RFC3339)
-
-	res, err := s.db.ExecContext(ctx, `
-		INSERT INTO habits(name, description, frequency, goal, color, icon, start_date, created_at, updated_at)
-		VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?)`,
-		h.Name, h.Description, h.Frequency, h.Goal, h.Color, h.Icon, h.StartDate, h.CreatedAt, h.UpdatedAt)
-	if err != nil {
-		return nil, err
+	// Imports carry their original timestamps; new habits get the current time.
+	if h.CreatedAt == "" {
+		h.CreatedAt = now.Format(time.RFC3339)
 	}
-	id, err := res.LastInsertId()
+	if h.UpdatedAt == "" {
+		h.UpdatedAt = now.Format(time.RFC3339)
+	}
+	var archivedAt any
+	if h.ArchivedAt != "" {
+		archivedAt = h.ArchivedAt
+	}
+	var id int64
+	err := s.withTx(ctx, func(tx *sql.Tx) error {
+		// New habits go to the end of the manual order.
+		if err := tx.QueryRowContext(ctx, `SELECT COALESCE(MAX(sort_order), 0) + 1 FROM habits`).Scan(&h.SortOrder); err != nil {
+			return err
+		}
+		res, err := tx.ExecContext(ctx, `
+			INSERT INTO habits(name, description, frequency, recurrence, goal, kind, unit, target, color, icon, start_date, created_at, updated_at, archived_at, sort_order)
+			VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			h.Name, h.Description, h.Frequency, h.Recurrence.Encode(), h.Goal, h.Kind, h.Unit, h.Target, h.Color, h.Icon, h.StartDate, h.CreatedAt, h.UpdatedAt, archivedAt, h.SortOrder)
+		if err != nil {
+			return err
+		}
+		if id, err = res.LastInsertId(); err != nil {
+			return err
+		}
+		return setHabitTags(ctx, tx, id, h.Tags)
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -201,13 +497,44 @@ func (s *SQLiteStore) UpdateHabit(ctx context.Context, h *models.Habit) error {
 	if h == nil || h.ID == 0 {
 		return errors.New("invalid habit")
 	}
-	normalizeHabitDefaults(h)
+	if err := normalizeHabitDefaults(h); err != nil {
+		return err
+	}
 	h.UpdatedAt = time.Now().Format(time.RFC3339)
-	_, err := s.db.ExecContext(ctx, `
-		UPDATE habits
-		SET name = ?, description = ?, frequency = ?, goal = ?, color = ?, icon = ?, start_date = ?, updated_at = ?
-		WHERE id = ?`,
-		h.Name, h.Description, h.Frequency, h.Goal, h.Color, h.Icon, h.StartDate, h.UpdatedAt, h.ID)
+	return s.withTx(ctx, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, `
+			UPDATE habits
+			SET name = ?, description = ?, frequency = ?, recurrence = ?, goal = ?, kind = ?, unit = ?, target = ?, color = ?, icon = ?, start_date = ?, updated_at = ?
+			WHERE id = ?`,
+			h.Name, h.Description, h.Frequency, h.Recurrence.Encode(), h.Goal, h.Kind, h.Unit, h.Target, h.Color, h.Icon, h.StartDate, h.UpdatedAt, h.ID); err != nil {
+			return err
+		}
+		return setHabitTags(ctx, tx, h.ID, h.Tags)
+	})
+}
+
+// setHabitTags replaces the habit's tags and drops tags no habit uses any more,
+// inside the transaction that writes the habit itself.
+func setHabitTags(ctx context.Context, tx *sql.Tx, habitID int64, tags []string) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM habit_tags WHERE habit_id = ?`, habitID); err != nil {
+		return err
+	}
+	for _, tag := range tags {
+		if _, err := tx.ExecContext(ctx, `INSERT INTO tags(name) VALUES(?) ON CONFLICT(name) DO NOTHING`, tag); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO habit_tags(habit_id, tag_id)
+			SELECT ?, id FROM tags WHERE name = ?`,
+			habitID, tag); err != nil {
+			return err
+		}
+	}
+	return pruneTags(ctx, tx)
+}
+
+func pruneTags(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `DELETE FROM tags WHERE id NOT IN (SELECT tag_id FROM habit_tags)`)
 	return err
 }
 
@@ -215,26 +542,45 @@ func (s *SQLiteStore) DeleteHabit(ctx context.Context, id int64) error {
 	if id == 0 {
 		return errors.New("invalid id")
 	}
-	tx, err := s.db.BeginTx(ctx, nil)
-	if err != nil {
-		return err
-	}
-	defer func() { _ = tx.Rollback() }()
-
-	if _, err := tx.ExecContext(ctx, `DELETE FROM completions WHERE habit_id = ?`, id); err != nil {
-		return err
-	}
-	if _, err := tx.ExecContext(ctx, `DELETE FROM habits WHERE id = ?`, id); err != nil {
-		return err
-	}
-	return tx.Commit()
+	return s.withTx(ctx, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM completions WHERE habit_id = ?`, id); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, `DELETE FROM habit_tags WHERE habit_id = ?`, id); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, `DELETE FROM skips WHERE habit_id = ?`, id); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, `DELETE FROM habits WHERE id = ?`, id); err != nil {
+			return err
+		}
+		return pruneTags(ctx, tx)
+	})
 }
 
+// ListHabits returns active (non-archived) habits.
 func (s *SQLiteStore) ListHabits(ctx context.Context) ([]models.Habit, error) {
-	rows, err := s.db.QueryContext(ctx, `
-		SELECT id, name, description, frequency, goal, color, icon, start_date, created_at, updated_at
+	return s.queryHabits(ctx, `
+		SELECT id, name, description, frequency, recurrence, goal, kind, unit, target, color, icon, start_date, created_at, updated_at,
+			COALESCE(archived_at, ''), sort_order
+		FROM habits
+		WHERE archived_at IS NULL
+		ORDER BY sort_order ASC, created_at ASC, id ASC`)
+}
+
+// ListArchivedHabits returns archived habits, most recently archived first.
+func (s *SQLiteStore) ListArchivedHabits(ctx context.Context) ([]models.Habit, error) {
+	return s.queryHabits(ctx, `
+		SELECT id, name, description, frequency, recurrence, goal, kind, unit, target, color, icon, start_date, created_at, updated_at,
+			COALESCE(archived_at, ''), sort_order
 		FROM habits
-		ORDER BY created_at ASC`)
+		WHERE archived_at IS NOT NULL
+		ORDER BY archived_at DESC`)
+}
+
+func (s *SQLiteStore) queryHabits(ctx context.Context, query string, args ...any) ([]models.Habit, error) {
+	rows, err := s.conn().QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -242,18 +588,134 @@ func (s *SQLiteStore) ListHabits(ctx context.Context) ([]models.Habit, error) {
 
 	var out []models.Habit
 	for rows.Next() {
-		var h models.Habit
+		var (
+			h          models.Habit
+			recurrence string
+		)
 		if err := rows.Scan(
-			&h.ID, &h.Name, &h.Description, &h.Frequency, &h.Goal, &h.Color, &h.Icon,
-			&h.StartDate, &h.CreatedAt, &h.UpdatedAt,
+			&h.ID, &h.Name, &h.Description, &h.Frequency, &recurrence, &h.Goal, &h.Kind, &h.Unit, &h.Target, &h.Color, &h.Icon,
+			&h.StartDate, &h.CreatedAt, &h.UpdatedAt, &h.ArchivedAt, &h.SortOrder,
 		); err != nil {
 			return nil, err
 		}
+		// An unreadable recurrence falls back to the weekday frequency rather than hiding the habit.
+		h.Recurrence, _ = models.ParseRecurrence(recurrence)
 		out = append(out, h)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+	if err := s.attachTags(ctx, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// attachTags fills in Tags for each habit in place.
+func (s *SQLiteStore) attachTags(ctx context.Context, habits []models.Habit) error {
+	if len(habits) == 0 {
+		return nil
+	}
+	rows, err := s.conn().QueryContext(ctx, `
+		SELECT ht.habit_id, t.name
+		FROM habit_tags ht
+		JOIN tags t ON t.id = ht.tag_id
+		ORDER BY t.name ASC`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	byHabit := make(map[int64][]string)
+	for rows.Next() {
+		var (
+			habitID int64
+			name    string
+		)
+		if err := rows.Scan(&habitID, &name); err != nil {
+			return err
+		}
+		byHabit[habitID] = append(byHabit[habitID], name)
+	}
+	for i := range habits {
+		habits[i].Tags = byHabit[habits[i].ID]
+	}
+	return rows.Err()
+}
+
+// ListTags returns the tags used by active habits, sorted by name.
+func (s *SQLiteStore) ListTags(ctx context.Context) ([]string, error) {
+	rows, err := s.conn().QueryContext(ctx, `
+		SELECT DISTINCT t.name
+		FROM tags t
+		JOIN habit_tags ht ON ht.tag_id = t.id
+		JOIN habits h ON h.id = ht.habit_id
+		WHERE h.archived_at IS NULL
+		ORDER BY t.name ASC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		out = append(out, name)
+	}
 	return out, rows.Err()
 }
 
+// ReorderHabits stores ids as the manual order: each habit's sort_order becomes
+// its position in ids. Habits not listed keep their current position.
+func (s *SQLiteStore) ReorderHabits(ctx context.Context, ids []int64) error {
+	return s.withTx(ctx, func(tx *sql.Tx) error {
+		for i, id := range ids {
+			if _, err := tx.ExecContext(ctx, `UPDATE habits SET sort_order = ? WHERE id = ?`, i+1, id); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ArchiveHabit hides a habit from the active list while keeping its completions.
+func (s *SQLiteStore) ArchiveHabit(ctx context.Context, id int64) error {
+	if id == 0 {
+		return errors.New("invalid id")
+	}
+	now := time.Now().Format(time.RFC3339)
+	_, err := s.conn().ExecContext(ctx, `
+		UPDATE habits SET archived_at = ?, updated_at = ?
+		WHERE id = ? AND archived_at IS NULL`,
+		now, now, id)
+	return err
+}
+
+// UnarchiveHabit returns an archived habit to the active list.
+func (s *SQLiteStore) UnarchiveHabit(ctx context.Context, id int64) error {
+	if id == 0 {
+		return errors.New("invalid id")
+	}
+	// Restored habits rejoin at the end of the manual order. Only archived
+	// habits match, so an active habit keeps its place.
+	res, err := s.conn().ExecContext(ctx, `
+		UPDATE habits SET archived_at = NULL, updated_at = ?,
+			sort_order = (SELECT COALESCE(MAX(sort_order), 0) + 1 FROM habits WHERE archived_at IS NULL)
+		WHERE id = ? AND archived_at IS NOT NULL`,
+		time.Now().Format(time.RFC3339), id)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
 func (s *SQLiteStore) CreateCompletion(ctx context.Context, c *models.Completion) (*models.Completion, error) {
 	if c == nil {
 		return nil, errors.New("completion is nil")
@@ -261,10 +723,17 @@ func (s *SQLiteStore) CreateCompletion(ctx context.Context, c *models.Completion
 	if c.CompletedAt == "" {
 		c.CompletedAt = time.Now().Format(time.RFC3339)
 	}
-	res, err := s.db.ExecContext(ctx, `
-		INSERT INTO completions(habit_id, completed_at)
-		VALUES(?, ?)`,
-		c.HabitID, c.CompletedAt)
+	if c.Value <= 0 {
+		c.Value = 1
+	}
+	c.LocalDate = models.CompletionDay(c.CompletedAt)
+	if c.LocalDate == "" {
+		return nil, fmt.Errorf("invalid completed_at %q", c.CompletedAt)
+	}
+	res, err := s.conn().ExecContext(ctx, `
+		INSERT INTO completions(habit_id, completed_at, local_date, value)
+		VALUES(?, ?, ?, ?)`,
+		c.HabitID, c.CompletedAt, c.LocalDate, c.Value)
 	if err != nil {
 		return nil, err
 	}
@@ -276,17 +745,43 @@ func (s *SQLiteStore) CreateCompletion(ctx context.Context, c *models.Completion
 	return c, nil
 }
 
+// UpdateCompletion changes a completion's time and value, moving it to the day
+// the new time counts toward.
+func (s *SQLiteStore) UpdateCompletion(ctx context.Context, c *models.Completion) error {
+	if c == nil || c.ID == 0 {
+		return errors.New("invalid id")
+	}
+	if c.Value <= 0 {
+		c.Value = 1
+	}
+	c.LocalDate = models.CompletionDay(c.CompletedAt)
+	if c.LocalDate == "" {
+		return fmt.Errorf("invalid completed_at %q", c.CompletedAt)
+	}
+	res, err := s.conn().ExecContext(ctx, `
+		UPDATE completions SET completed_at = ?, local_date = ?, value = ?
+		WHERE id = ?`,
+		c.CompletedAt, c.LocalDate, c.Value, c.ID)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("completion %d not found", c.ID)
+	}
+	return nil
+}
+
 func (s *SQLiteStore) DeleteCompletion(ctx context.Context, id int64) error {
 	if id == 0 {
 		return errors.New("invalid id")
 	}
-	_, err := s.db.ExecContext(ctx, `DELETE FROM completions WHERE id = ?`, id)
+	_, err := s.conn().ExecContext(ctx, `DELETE FROM completions WHERE id = ?`, id)
 	return err
 }
 
 func (s *SQLiteStore) ListCompletions(ctx context.Context) ([]models.Completion, error) {
-	rows, err := s.db.QueryContext(ctx, `
-		SELECT id, habit_id, completed_at
+	rows, err := s.conn().QueryContext(ctx, `
+		SELECT id, habit_id, completed_at, local_date, value
 		FROM completions
 		ORDER BY completed_at ASC`)
 	if err != nil {
@@ -296,17 +791,11 @@ func (s *SQLiteStore) ListCompletions(ctx context.Context) ([]models.Completion,
 	return scanCompletions(rows)
 }
 
-func dayBounds(date time.Time) (time.Time, time.Time) {
-	start := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
-	end := time.Date(date.Year(), date.Month(), date.Day(), 23, 59, 59, 999999999, date.Location())
-	return start, end
-}
-
 func scanCompletions(rows *sql.Rows) ([]models.Completion, error) {
 	var completions []models.Completion
 	for rows.Next() {
 		var c models.Completion
-		if err := rows.Scan(&c.ID, &c.HabitID, &c.CompletedAt); err != nil {
+		if err := rows.Scan(&c.ID, &c.HabitID, &c.CompletedAt, &c.LocalDate, &c.Value); err != nil {
 			return nil, err
 		}
 		completions = append(completions, c)
@@ -314,62 +803,22 @@ func scanCompletions(rows *sql.Rows) ([]models.Completion, error) {
 	return completions, rows.Err()
 }
 
-// filterCompletionsInRange keeps completions whose parsed timestamp falls in [start, end].
-// This avoids fragile lexicographic compares when offsets differ (local vs UTC).
-func filterCompletionsInRange(completions []models.Completion, start, end time.Time) []models.Completion {
-	var out []models.Completion
-	for _, c := range completions {
-		completedAt, err := time.Parse(time.RFC3339, c.CompletedAt)
-		if err != nil {
-			continue
-		}
-		if (completedAt.Equal(start) || completedAt.After(start)) &&
-			(completedAt.Equal(end) || completedAt.Before(end)) {
-			out = append(out, c)
-		}
-	}
-	return out
-}
-
-func (s *SQLiteStore) queryCompletionsInPaddedRange(ctx context.Context, start, end time.Time, habitID *int64) ([]models.Completion, error) {
-	var (
-		rows *sql.Rows
-		err  error
-	)
-	padStart := start.Add(-24 * time.Hour).Format(time.RFC3339)
-	padEnd := end.Add(24 * time.Hour).Format(time.RFC3339)
-	if habitID != nil {
-		rows, err = s.db.QueryContext(ctx, `
-			SELECT id, habit_id, completed_at
-			FROM completions
-			WHERE habit_id = ? AND completed_at >= ? AND completed_at <= ?`,
-			*habitID, padStart, padEnd)
-	} else {
-		rows, err = s.db.QueryContext(ctx, `
-			SELECT id, habit_id, completed_at
-			FROM completions
-			WHERE completed_at >= ? AND completed_at <= ?`,
-			padStart, padEnd)
-	}
+func (s *SQLiteStore) GetCompletionsByHabitIDAndDate(ctx context.Context, habitID int64, date time.Time) ([]models.Completion, error) {
+	rows, err := s.conn().QueryContext(ctx, `
+		SELECT id, habit_id, completed_at, local_date, value
+		FROM completions
+		WHERE local_date = ? AND habit_id = ?`,
+		models.DayKey(date), habitID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	completions, err := scanCompletions(rows)
-	if err != nil {
-		return nil, err
-	}
-	return filterCompletionsInRange(completions, start, end), nil
-}
-
-func (s *SQLiteStore) GetCompletionsByHabitIDAndDate(ctx context.Context, habitID int64, date time.Time) ([]models.Completion, error) {
-	start, end := dayBounds(date)
-	return s.queryCompletionsInPaddedRange(ctx, start, end, &habitID)
+	return scanCompletions(rows)
 }
 
 func (s *SQLiteStore) GetCompletionsByHabitID(ctx context.Context, habitID int64) ([]models.Completion, error) {
-	rows, err := s.db.QueryContext(ctx, `
-		SELECT id, habit_id, completed_at
+	rows, err := s.conn().QueryContext(ctx, `
+		SELECT id, habit_id, completed_at, local_date, value
 		FROM completions
 		WHERE habit_id = ?`,
 		habitID)
@@ -381,14 +830,66 @@ func (s *SQLiteStore) GetCompletionsByHabitID(ctx context.Context, habitID int64
 }
 
 func (s *SQLiteStore) GetCompletionsByDate(ctx context.Context, date time.Time) ([]models.Completion, error) {
-	start, end := dayBounds(date)
-	return s.queryCompletionsInPaddedRange(ctx, start, end, nil)
+	return s.GetCompletionsByDateRange(ctx, date, date)
 }
 
+// GetCompletionsByDateRange returns completions on local days startDate
+// through endDate inclusive.
 func (s *SQLiteStore) GetCompletionsByDateRange(ctx context.Context, startDate, endDate time.Time) ([]models.Completion, error) {
-	start, _ := dayBounds(startDate)
-	_, end := dayBounds(endDate)
-	return s.queryCompletionsInPaddedRange(ctx, start, end, nil)
+	rows, err := s.conn().QueryContext(ctx, `
+		SELECT id, habit_id, completed_at, local_date, value
+		FROM completions
+		WHERE local_date BETWEEN ? AND ?`,
+		models.DayKey(startDate), models.DayKey(endDate))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanCompletions(rows)
+}
+
+// GetDailyTotals aggregates completions per habit and local day for days
+// startDate through endDate inclusive.
+func (s *SQLiteStore) GetDailyTotals(ctx context.Context, startDate, endDate time.Time) ([]models.DailyTotal, error) {
+	return s.dailyTotals(ctx, "local_date BETWEEN ? AND ?",
+		models.DayKey(startDate), models.DayKey(endDate))
+}
+
+// GetDailyTotalsByHabitIDs is GetDailyTotals limited to the given habits.
+func (s *SQLiteStore) GetDailyTotalsByHabitIDs(ctx context.Context, habitIDs []int64, startDate, endDate time.Time) ([]models.DailyTotal, error) {
+	if len(habitIDs) == 0 {
+		return nil, nil
+	}
+	args := []any{models.DayKey(startDate), models.DayKey(endDate)}
+	for _, id := range habitIDs {
+		args = append(args, id)
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(habitIDs)), ", ")
+	return s.dailyTotals(ctx, "local_date BETWEEN ? AND ? AND habit_id IN ("+placeholders+")", args...)
+}
+
+// dailyTotals sums the completions matching where per habit and day.
+func (s *SQLiteStore) dailyTotals(ctx context.Context, where string, args ...any) ([]models.DailyTotal, error) {
+	rows, err := s.conn().QueryContext(ctx, `
+		SELECT habit_id, local_date, COUNT(*), COALESCE(SUM(value), 0)
+		FROM completions
+		WHERE `+where+`
+		GROUP BY habit_id, local_date`,
+		args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var totals []models.DailyTotal
+	for rows.Next() {
+		var t models.DailyTotal
+		if err := rows.Scan(&t.HabitID, &t.Day, &t.Count, &t.Value); err != nil {
+			return nil, err
+		}
+		totals = append(totals, t)
+	}
+	return totals, rows.Err()
 }
 
 var _ Store = (*SQLiteStore)(nil)
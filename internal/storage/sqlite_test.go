@@ -2,7 +2,10 @@ package storage_test
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -12,7 +15,7 @@ import (
 
 func openTestStore(t *testing.T) *storage.SQLiteStore {
 	t.Helper()
-	store, err := storage.OpenSQLiteAt(filepath.Join(t.TempDir(), "habit.db"))
+	store, err := storage.OpenSQLiteAt(filepath.Join(t.TempDir(), "habit.db"), nil)
 	if err != nil {
 		t.Fatalf("open store: %v", err)
 	}
@@ -44,6 +47,43 @@ func TestCreateCompletionSetsID(t *testing.T) {
 	}
 }
 
+func TestUpdateCompletionMovesDay(t *testing.T) {
+	store := openTestStore(t)
+	ctx := context.Background()
+	habit, err := store.CreateHabit(ctx, &models.Habit{Name: "Read", Kind: models.HabitKindQuantity, Target: 30})
+	if err != nil {
+		t.Fatalf("create habit: %v", err)
+	}
+	at := time.Date(2026, 7, 10, 21, 0, 0, 0, time.Local)
+	c, err := store.CreateCompletion(ctx, &models.Completion{HabitID: habit.ID, CompletedAt: at.Format(time.RFC3339), Value: 10})
+	if err != nil {
+		t.Fatalf("create completion: %v", err)
+	}
+
+	c.CompletedAt = at.AddDate(0, 0, -1).Add(-12 * time.Hour).Format(time.RFC3339)
+	c.Value = 25
+	if err := store.UpdateCompletion(ctx, c); err != nil {
+		t.Fatalf("update completion: %v", err)
+	}
+	if c.LocalDate != "2026-07-09" {
+		t.Fatalf("LocalDate = %s, want 2026-07-09", c.LocalDate)
+	}
+	got, err := store.GetCompletionsByHabitIDAndDate(ctx, habit.ID, at.AddDate(0, 0, -1))
+	if err != nil || len(got) != 1 || got[0].Value != 25 || got[0].CompletedAt != c.CompletedAt {
+		t.Fatalf("completions on the new day = %+v, %v", got, err)
+	}
+	if got, _ := store.GetCompletionsByHabitIDAndDate(ctx, habit.ID, at); len(got) != 0 {
+		t.Fatalf("completion still on the old day: %+v", got)
+	}
+
+	if err := store.UpdateCompletion(ctx, &models.Completion{ID: c.ID, CompletedAt: "yesterday"}); err == nil {
+		t.Fatal("expected an error for an invalid time")
+	}
+	if err := store.UpdateCompletion(ctx, &models.Completion{ID: 999, CompletedAt: c.CompletedAt}); err == nil {
+		t.Fatal("expected an error for a missing completion")
+	}
+}
+
 func TestDeleteHabitRemovesCompletions(t *testing.T) {
 	store := openTestStore(t)
 	ctx := context.Background()
@@ -134,3 +174,476 @@ func TestCreateHabitDefaults(t *testing.T) {
 		t.Fatalf("expected frequency daily, got %q", habit.Frequency)
 	}
 }
+
+func TestCreateHabitValidatesColor(t *testing.T) {
+	store := openTestStore(t)
+	ctx := context.Background()
+	habit, err := store.CreateHabit(ctx, &models.Habit{Name: "Hex", Color: "#ABC", StartDate: time.Now().Format(time.RFC3339)})
+	if err != nil {
+		t.Fatalf("create habit: %v", err)
+	}
+	if habit.Color != "#aabbcc" {
+		t.Fatalf("color = %q, want #aabbcc", habit.Color)
+	}
+	if _, err := store.CreateHabit(ctx, &models.Habit{Name: "Bad", Color: "teal", StartDate: time.Now().Format(time.RFC3339)}); err == nil {
+		t.Fatal("expected an error for an unknown color")
+	}
+	habit.Color = "999"
+	if err := store.UpdateHabit(ctx, habit); err == nil {
+		t.Fatal("expected an error for an out-of-range color index")
+	}
+}
+
+func TestArchiveHabitKeepsCompletions(t *testing.T) {
+	store := openTestStore(t)
+	ctx := context.Background()
+
+	habit, err := store.CreateHabit(ctx, &models.Habit{
+		Name:      "Challenge",
+		StartDate: time.Now().Format(time.RFC3339),
+	})
+	if err != nil {
+		t.Fatalf("create habit: %v", err)
+	}
+	if _, err := store.CreateCompletion(ctx, &models.Completion{
+		HabitID:     habit.ID,
+		CompletedAt: time.Now().Format(time.RFC3339),
+	}); err != nil {
+		t.Fatalf("create completion: %v", err)
+	}
+
+	if err := store.ArchiveHabit(ctx, habit.ID); err != nil {
+		t.Fatalf("archive habit: %v", err)
+	}
+	active, err := store.ListHabits(ctx)
+	if err != nil {
+		t.Fatalf("list habits: %v", err)
+	}
+	if len(active) != 0 {
+		t.Fatalf("expected archived habit to be hidden, got %d active", len(active))
+	}
+	archived, err := store.ListArchivedHabits(ctx)
+	if err != nil {
+		t.Fatalf("list archived: %v", err)
+	}
+	if len(archived) != 1 || archived[0].ArchivedAt == "" {
+		t.Fatalf("expected 1 archived habit with archived_at, got %+v", archived)
+	}
+	completions, err := store.GetCompletionsByHabitID(ctx, habit.ID)
+	if err != nil {
+		t.Fatalf("list completions: %v", err)
+	}
+	if len(completions) != 1 {
+		t.Fatalf("expected archived habit to keep 1 completion, got %d", len(completions))
+	}
+
+	if err := store.UnarchiveHabit(ctx, habit.ID); err != nil {
+		t.Fatalf("unarchive habit: %v", err)
+	}
+	active, err = store.ListHabits(ctx)
+	if err != nil {
+		t.Fatalf("list habits: %v", err)
+	}
+	if len(active) != 1 || active[0].ArchivedAt != "" {
+		t.Fatalf("expected restored habit to be active, got %+v", active)
+	}
+	if err := store.UnarchiveHabit(ctx, habit.ID); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("unarchive active habit: err = %v, want sql.ErrNoRows", err)
+	}
+}
+
+func TestQuantityHabitAndCompletionValue(t *testing.T) {
+	store := openTestStore(t)
+	ctx := context.Background()
+
+	habit, err := store.CreateHabit(ctx, &models.Habit{
+		Name:      "Read",
+		Kind:      models.HabitKindQuantity,
+		Unit:      "pages",
+		Target:    30,
+		StartDate: time.Now().Format(time.RFC3339),
+	})
+	if err != nil {
+		t.Fatalf("create habit: %v", err)
+	}
+	if _, err := store.CreateCompletion(ctx, &models.Completion{HabitID: habit.ID, Value: 12.5}); err != nil {
+		t.Fatalf("create completion: %v", err)
+	}
+	if _, err := store.CreateCompletion(ctx, &models.Completion{HabitID: habit.ID}); err != nil {
+		t.Fatalf("create completion: %v", err)
+	}
+
+	habits, err := store.ListHabits(ctx)
+	if err != nil {
+		t.Fatalf("list habits: %v", err)
+	}
+	if got := habits[0]; got.Kind != models.HabitKindQuantity || got.Unit != "pages" || got.Target != 30 {
+		t.Fatalf("habit round-trip = %+v", got)
+	}
+	completions, err := store.GetCompletionsByHabitID(ctx, habit.ID)
+	if err != nil {
+		t.Fatalf("list completions: %v", err)
+	}
+	if len(completions) != 2 || completions[0].Value != 12.5 || completions[1].Value != 1 {
+		t.Fatalf("completion values = %+v, want 12.5 and default 1", completions)
+	}
+}
+
+func TestReorderHabits(t *testing.T) {
+	store := openTestStore(t)
+	ctx := context.Background()
+
+	var ids []int64
+	for _, name := range []string{"Run", "Read", "Write"} {
+		h, err := store.CreateHabit(ctx, &models.Habit{Name: name})
+		if err != nil {
+			t.Fatalf("create habit: %v", err)
+		}
+		ids = append(ids, h.ID)
+	}
+	if err := store.ReorderHabits(ctx, []int64{ids[2], ids[0], ids[1]}); err != nil {
+		t.Fatalf("reorder: %v", err)
+	}
+	habits, err := store.ListHabits(ctx)
+	if err != nil {
+		t.Fatalf("list habits: %v", err)
+	}
+	var names []string
+	for _, h := range habits {
+		names = append(names, h.Name)
+	}
+	if got := strings.Join(names, ","); got != "Write,Run,Read" {
+		t.Fatalf("order = %s, want Write,Run,Read", got)
+	}
+
+	// A restored habit rejoins at the end.
+	if err := store.ArchiveHabit(ctx, ids[2]); err != nil {
+		t.Fatalf("archive: %v", err)
+	}
+	if err := store.UnarchiveHabit(ctx, ids[2]); err != nil {
+		t.Fatalf("unarchive: %v", err)
+	}
+	habits, _ = store.ListHabits(ctx)
+	if habits[len(habits)-1].ID != ids[2] {
+		t.Fatalf("restored habit should be last, got %q", habits[len(habits)-1].Name)
+	}
+}
+
+func TestHabitTags(t *testing.T) {
+	store := openTestStore(t)
+	ctx := context.Background()
+
+	run, err := store.CreateHabit(ctx, &models.Habit{Name: "Run", Tags: []string{"Health", " #fitness ", "health"}})
+	if err != nil {
+		t.Fatalf("create habit: %v", err)
+	}
+	read, err := store.CreateHabit(ctx, &models.Habit{Name: "Read", Tags: []string{"learning"}})
+	if err != nil {
+		t.Fatalf("create habit: %v", err)
+	}
+
+	habits, err := store.ListHabits(ctx)
+	if err != nil {
+		t.Fatalf("list habits: %v", err)
+	}
+	if got := strings.Join(habits[0].Tags, ","); got != "fitness,health" {
+		t.Fatalf("tags = %q, want fitness,health", got)
+	}
+
+	read.Tags = []string{"health"}
+	if err := store.UpdateHabit(ctx, read); err != nil {
+		t.Fatalf("update habit: %v", err)
+	}
+	if err := store.ArchiveHabit(ctx, run.ID); err != nil {
+		t.Fatalf("archive: %v", err)
+	}
+	tags, err := store.ListTags(ctx)
+	if err != nil {
+		t.Fatalf("list tags: %v", err)
+	}
+	if got := strings.Join(tags, ","); got != "health" {
+		t.Fatalf("active tags = %q, want health (learning unused, fitness archived)", got)
+	}
+
+	if err := store.DeleteHabit(ctx, run.ID); err != nil {
+		t.Fatalf("delete habit: %v", err)
+	}
+	archived, _ := store.ListArchivedHabits(ctx)
+	if len(archived) != 0 {
+		t.Fatalf("expected no archived habits, got %d", len(archived))
+	}
+}
+
+func TestHabitWriteRollsBackWithTags(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "habit.db")
+	store, err := storage.OpenSQLiteAt(path, nil)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	defer store.Close()
+	ctx := context.Background()
+	read, err := store.CreateHabit(ctx, &models.Habit{Name: "Read"})
+	if err != nil {
+		t.Fatalf("create habit: %v", err)
+	}
+
+	// Make writing one tag fail after the habit row has been written.
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatalf("open raw db: %v", err)
+	}
+	if _, err := db.Exec(`CREATE TRIGGER reject_tag BEFORE INSERT ON tags WHEN NEW.name = 'broken'
+		BEGIN SELECT RAISE(ABORT, 'rejected tag'); END`); err != nil {
+		t.Fatalf("create trigger: %v", err)
+	}
+	_ = db.Close()
+
+	if _, err := store.CreateHabit(ctx, &models.Habit{Name: "Run", Tags: []string{"broken"}}); err == nil {
+		t.Fatal("expected create to fail on the rejected tag")
+	}
+	read.Name = "Read more"
+	read.Tags = []string{"broken"}
+	if err := store.UpdateHabit(ctx, read); err == nil {
+		t.Fatal("expected update to fail on the rejected tag")
+	}
+	habits, err := store.ListHabits(ctx)
+	if err != nil {
+		t.Fatalf("list habits: %v", err)
+	}
+	if len(habits) != 1 || habits[0].Name != "Read" {
+		t.Fatalf("habits = %+v, want only the unchanged Read", habits)
+	}
+}
+
+func TestSkipsAndVacations(t *testing.T) {
+	store := openTestStore(t)
+	ctx := context.Background()
+
+	run, err := store.CreateHabit(ctx, &models.Habit{Name: "Run"})
+	if err != nil {
+		t.Fatalf("create habit: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := store.CreateSkip(ctx, &models.Skip{HabitID: run.ID, Day: "2026-07-08"}); err != nil {
+			t.Fatalf("create skip: %v", err)
+		}
+	}
+	if _, err := store.CreateSkip(ctx, &models.Skip{Day: "2026-07-09"}); err != nil {
+		t.Fatalf("create all-habits skip: %v", err)
+	}
+	skips, err := store.ListSkips(ctx)
+	if err != nil {
+		t.Fatalf("list skips: %v", err)
+	}
+	if len(skips) != 2 {
+		t.Fatalf("skips = %+v, want 2 (duplicate ignored)", skips)
+	}
+
+	if _, err := store.CreateVacation(ctx, &models.Vacation{StartDay: "2026-07-12", EndDay: "2026-07-10"}); err == nil {
+		t.Fatal("expected an error for a reversed vacation")
+	}
+	v, err := store.CreateVacation(ctx, &models.Vacation{StartDay: "2026-07-10", EndDay: "2026-07-12", Note: "Beach"})
+	if err != nil {
+		t.Fatalf("create vacation: %v", err)
+	}
+
+	pauses, err := storage.LoadPauses(ctx, store)
+	if err != nil {
+		t.Fatalf("load pauses: %v", err)
+	}
+	day := func(d int) time.Time { return time.Date(2026, 7, d, 9, 0, 0, 0, time.Local) }
+	if !pauses.IsPaused(run.ID, day(8)) || !pauses.IsPaused(run.ID+1, day(9)) || !pauses.IsPaused(run.ID, day(11)) {
+		t.Fatal("expected Jul 8 (skip), Jul 9 (all habits) and Jul 11 (vacation) to be paused")
+	}
+	if pauses.IsPaused(run.ID+1, day(8)) || pauses.IsPaused(run.ID, day(13)) {
+		t.Fatal("expected other habits on Jul 8 and every habit on Jul 13 to be due")
+	}
+
+	if err := store.DeleteVacation(ctx, v.ID); err != nil {
+		t.Fatalf("delete vacation: %v", err)
+	}
+	if err := store.DeleteHabit(ctx, run.ID); err != nil {
+		t.Fatalf("delete habit: %v", err)
+	}
+	vacations, _ := store.ListVacations(ctx)
+	skips, _ = store.ListSkips(ctx)
+	if len(vacations) != 0 || len(skips) != 1 || skips[0].HabitID != 0 {
+		t.Fatalf("after deletes: vacations %+v, skips %+v; want only the all-habits skip", vacations, skips)
+	}
+}
+
+func TestMigrationBackfillsLocalDate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "habit.db")
+	store, err := storage.OpenSQLiteAt(path, nil)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	ctx := context.Background()
+	habit, err := store.CreateHabit(ctx, &models.Habit{Name: "Read"})
+	if err != nil {
+		t.Fatalf("create habit: %v", err)
+	}
+	// Late evening local time is the next day in UTC for zones west of it,
+	// and vice versa; the key must follow the local day either way.
+	at := time.Date(2026, 7, 10, 23, 30, 0, 0, time.Local)
+	if _, err := store.CreateCompletion(ctx, &models.Completion{HabitID: habit.ID, CompletedAt: at.UTC().Format(time.RFC3339)}); err != nil {
+		t.Fatalf("create completion: %v", err)
+	}
+	_ = store.Close()
+
+	// Simulate a database from before the column existed.
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatalf("open raw db: %v", err)
+	}
+	if _, err := db.Exec(`UPDATE completions SET local_date = ''`); err != nil {
+		t.Fatalf("clear local_date: %v", err)
+	}
+	if _, err := db.Exec(`DELETE FROM schema_migrations WHERE version >= 9`); err != nil {
+		t.Fatalf("rewind migrations: %v", err)
+	}
+	_ = db.Close()
+
+	store, err = storage.OpenSQLiteAt(path, nil)
+	if err != nil {
+		t.Fatalf("reopen store: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	got, err := store.GetCompletionsByDateRange(ctx, at, at)
+	if err != nil {
+		t.Fatalf("get by range: %v", err)
+	}
+	if len(got) != 1 || got[0].LocalDate != "2026-07-10" {
+		t.Fatalf("completions = %+v, want one keyed 2026-07-10", got)
+	}
+}
+
+func TestDayRolloverRekeysCompletions(t *testing.T) {
+	defer models.SetDayRollover(0)
+	path := filepath.Join(t.TempDir(), "habit.db")
+	store, err := storage.OpenSQLiteAt(path, nil)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	ctx := context.Background()
+	habit, err := store.CreateHabit(ctx, &models.Habit{Name: "Gym"})
+	if err != nil {
+		t.Fatalf("create habit: %v", err)
+	}
+	lateNight := time.Date(2026, 7, 11, 0, 30, 0, 0, time.Local)
+	if _, err := store.CreateCompletion(ctx, &models.Completion{HabitID: habit.ID, CompletedAt: lateNight.Format(time.RFC3339)}); err != nil {
+		t.Fatalf("create completion: %v", err)
+	}
+	_ = store.Close()
+
+	keyOf := func(rollover time.Duration) string {
+		t.Helper()
+		store, err := storage.OpenSQLiteAt(path, &rollover)
+		if err != nil {
+			t.Fatalf("reopen store: %v", err)
+		}
+		defer store.Close()
+		all, err := store.ListCompletions(ctx)
+		if err != nil || len(all) != 1 {
+			t.Fatalf("list completions = %+v, %v", all, err)
+		}
+		return all[0].LocalDate
+	}
+	if got := keyOf(0); got != "2026-07-11" {
+		t.Fatalf("local_date = %s, want the calendar day", got)
+	}
+	if got := keyOf(3 * time.Hour); got != "2026-07-10" {
+		t.Fatalf("local_date after day_ends_at 03:00 = %s, want the previous day", got)
+	}
+	if got := keyOf(0); got != "2026-07-11" {
+		t.Fatalf("local_date after resetting day_ends_at = %s", got)
+	}
+}
+
+func TestInvalidDayRolloverKeepsStoredDays(t *testing.T) {
+	defer models.SetDayRollover(0)
+	rollover := 3 * time.Hour
+	path := filepath.Join(t.TempDir(), "habit.db")
+	store, err := storage.OpenSQLiteAt(path, &rollover)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	ctx := context.Background()
+	habit, err := store.CreateHabit(ctx, &models.Habit{Name: "Gym"})
+	if err != nil {
+		t.Fatalf("create habit: %v", err)
+	}
+	lateNight := time.Date(2026, 7, 11, 0, 30, 0, 0, time.Local)
+	if _, err := store.CreateCompletion(ctx, &models.Completion{HabitID: habit.ID, CompletedAt: lateNight.Format(time.RFC3339)}); err != nil {
+		t.Fatalf("create completion: %v", err)
+	}
+	_ = store.Close()
+
+	// What main does when the config or day_ends_at doesn't parse.
+	models.SetDayRollover(0)
+	store, err = storage.OpenSQLiteAt(path, nil)
+	if err != nil {
+		t.Fatalf("reopen store: %v", err)
+	}
+	defer store.Close()
+	all, err := store.ListCompletions(ctx)
+	if err != nil || len(all) != 1 {
+		t.Fatalf("list completions = %+v, %v", all, err)
+	}
+	if all[0].LocalDate != "2026-07-10" {
+		t.Fatalf("local_date = %s, want it left on 2026-07-10", all[0].LocalDate)
+	}
+	if got := models.DayRollover(); got != 3*time.Hour {
+		t.Fatalf("DayRollover = %v, want the stored 3h", got)
+	}
+}
+
+func TestGetDailyTotals(t *testing.T) {
+	store := openTestStore(t)
+	ctx := context.Background()
+
+	habit, err := store.CreateHabit(ctx, &models.Habit{Name: "Water", Kind: models.HabitKindQuantity, Target: 2, Unit: "l"})
+	if err != nil {
+		t.Fatalf("create habit: %v", err)
+	}
+	at := func(day, hour int) string {
+		return time.Date(2026, 7, day, hour, 0, 0, 0, time.Local).Format(time.RFC3339)
+	}
+	for _, c := range []models.Completion{
+		{HabitID: habit.ID, CompletedAt: at(9, 8), Value: 0.5},
+		{HabitID: habit.ID, CompletedAt: at(10, 8), Value: 0.5},
+		{HabitID: habit.ID, CompletedAt: at(10, 20), Value: 1.25},
+		{HabitID: habit.ID, CompletedAt: at(12, 9), Value: 3},
+	} {
+		if _, err := store.CreateCompletion(ctx, &c); err != nil {
+			t.Fatalf("create completion: %v", err)
+		}
+	}
+
+	totals, err := store.GetDailyTotals(ctx, time.Date(2026, 7, 10, 0, 0, 0, 0, time.Local), time.Date(2026, 7, 12, 0, 0, 0, 0, time.Local))
+	if err != nil {
+		t.Fatalf("daily totals: %v", err)
+	}
+	byDay := make(map[string]models.DailyTotal)
+	for _, tot := range totals {
+		byDay[tot.Day] = tot
+	}
+	if len(totals) != 2 || byDay["2026-07-10"].Count != 2 || byDay["2026-07-10"].Value != 1.75 || byDay["2026-07-12"].Value != 3 {
+		t.Fatalf("totals = %+v, want Jul 10 (2 entries, 1.75) and Jul 12 (3)", totals)
+	}
+
+	other, err := store.CreateHabit(ctx, &models.Habit{Name: "Run"})
+	if err != nil {
+		t.Fatalf("create habit: %v", err)
+	}
+	if _, err := store.CreateCompletion(ctx, &models.Completion{HabitID: other.ID, CompletedAt: at(10, 7)}); err != nil {
+		t.Fatalf("create completion: %v", err)
+	}
+	totals, err = store.GetDailyTotalsByHabitIDs(ctx, []int64{other.ID}, time.Time{}, time.Date(2026, 7, 12, 0, 0, 0, 0, time.Local))
+	if err != nil {
+		t.Fatalf("daily totals by habit: %v", err)
+	}
+	if len(totals) != 1 || totals[0].HabitID != other.ID || totals[0].Day != "2026-07-10" {
+		t.Fatalf("totals by habit = %+v, want only Run on Jul 10", totals)
+	}
+}
@@ -12,14 +12,32 @@ type Store interface {
 	UpdateHabit(ctx context.Context, h *models.Habit) error
 	DeleteHabit(ctx context.Context, id int64) error
 	ListHabits(ctx context.Context) ([]models.Habit, error)
+	ListArchivedHabits(ctx context.Context) ([]models.Habit, error)
+	ArchiveHabit(ctx context.Context, id int64) error
+	UnarchiveHabit(ctx context.Context, id int64) error
+	ReorderHabits(ctx context.Context, ids []int64) error
+	ListTags(ctx context.Context) ([]string, error)
 
 	CreateCompletion(ctx context.Context, c *models.Completion) (*models.Completion, error)
+	UpdateCompletion(ctx context.Context, c *models.Completion) error
 	DeleteCompletion(ctx context.Context, id int64) error
 	ListCompletions(ctx context.Context) ([]models.Completion, error)
 	GetCompletionsByHabitID(ctx context.Context, habitID int64) ([]models.Completion, error)
 	GetCompletionsByHabitIDAndDate(ctx context.Context, habitID int64, date time.Time) ([]models.Completion, error)
 	GetCompletionsByDate(ctx context.Context, date time.Time) ([]models.Completion, error)
 	GetCompletionsByDateRange(ctx context.Context, startDate, endDate time.Time) ([]models.Completion, error)
+	GetDailyTotals(ctx context.Context, startDate, endDate time.Time) ([]models.DailyTotal, error)
+	GetDailyTotalsByHabitIDs(ctx context.Context, habitIDs []int64, startDate, endDate time.Time) ([]models.DailyTotal, error)
 
+	CreateSkip(ctx context.Context, s *models.Skip) (*models.Skip, error)
+	DeleteSkip(ctx context.Context, habitID int64, day string) error
+	ListSkips(ctx context.Context) ([]models.Skip, error)
+	CreateVacation(ctx context.Context, v *models.Vacation) (*models.Vacation, error)
+	DeleteVacation(ctx context.Context, id int64) error
+	ListVacations(ctx context.Context) ([]models.Vacation, error)
+
+	// InTx runs fn against a store whose writes commit together, or not at
+	// all when fn returns an error.
+	InTx(ctx context.Context, fn func(Store) error) error
 	Close() error
 }
@@ -2,6 +2,7 @@ package theme
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
@@ -30,8 +31,17 @@ type Theme struct {
 }
 
 type Config struct {
-	Theme string     `json:"theme,omitempty"`
-	Base  BaseColors `json:"base,omitzero"`
+	Theme    string     `json:"theme,omitempty"`
+	Base     BaseColors `json:"base,omitzero"`
+	SortMode string     `json:"sort_mode,omitempty"` // habit list order: manual, incomplete, streak, color
+	// Keys rebinds actions, e.g. {"up": ["e"], "down": ["n"]}; see keymap.Actions.
+	Keys map[string][]string `json:"keys,omitempty"`
+	// WeekStart is the first day of the calendar week: monday (default), sunday or saturday.
+	WeekStart string `json:"week_start,omitempty"`
+	// DayEndsAt moves the day boundary past midnight, e.g. "03:00".
+	DayEndsAt string `json:"day_ends_at,omitempty"`
+	// NerdFont adds Nerd Font glyphs to the icon catalog.
+	NerdFont bool `json:"nerd_font,omitempty"`
 }
 
 func GetConfigPath() string {
@@ -65,8 +75,7 @@ func LoadConfig() (*Config, error) {
 
 	var config Config
 	if err := json.Unmarshal(data, &config); err != nil {
-		defaultConfig := GetDefaultConfig()
-		return &defaultConfig, nil
+		return nil, fmt.Errorf("parse %s: %w", configPath, err)
 	}
 	migrateLegacyColorKeys(data, &config)
 
@@ -160,27 +169,30 @@ func GetTheme(config *Config) Theme {
 	return theme
 }
 
-// LookupTheme returns a built-in theme by name, falling back to the default.
+// LookupTheme returns a built-in or user theme by name, falling back to the default.
 func LookupTheme(name string) Theme {
 	name = normalizeThemeName(name)
-	for _, t := range AllThemes {
+	for _, t := range themes {
 		if t.Name == name {
 			return t
 		}
 	}
+	if name != DefaultThemeName {
+		return LookupTheme(DefaultThemeName)
+	}
 	return CatppuccinMocha
 }
 
 // NextThemeName returns the next theme id after current.
 func NextThemeName(current string) string {
 	current = normalizeThemeName(current)
-	if len(AllThemes) == 0 {
+	if len(themes) == 0 {
 		return DefaultThemeName
 	}
-	for i, t := range AllThemes {
+	for i, t := range themes {
 		if t.Name == current {
-			return AllThemes[(i+1)%len(AllThemes)].Name
+			return themes[(i+1)%len(themes)].Name
 		}
 	}
-	return AllThemes[0].Name
+	return themes[0].Name
 }
@@ -83,6 +83,21 @@ func TestSaveAndLoadConfig(t *testing.T) {
 	}
 }
 
+func TestLoadConfigReportsMalformedFile(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+	if err := os.MkdirAll(filepath.Join(dir, ".habitui"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, ".habitui", "habitui.config")
+	if err := os.WriteFile(path, []byte(`{"day_ends_at": "03:00",}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if cfg, err := LoadConfig(); err == nil {
+		t.Fatalf("LoadConfig = %+v, want an error for the trailing comma", cfg)
+	}
+}
+
 func TestSaveConfigOmitsEmptyBaseFields(t *testing.T) {
 	dir := t.TempDir()
 	t.Setenv("HOME", dir)
@@ -162,3 +177,107 @@ func TestAllThemesHaveSemanticSlots(t *testing.T) {
 		}
 	}
 }
+
+func TestLoadUserThemes(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	full, err := json.Marshal(Theme{Name: "Acme", Base: Nord.Base})
+	if err != nil {
+		t.Fatal(err)
+	}
+	write("acme.json", string(full))
+	write("flat.yaml", `scheme: "Flat Scheme"
+author: "someone"
+base00: "1e1e2e"
+base01: "181825"
+base02: "313244"
+base03: "45475a"
+base04: "585b70"
+base05: "cdd6f4"
+base06: "f5e0dc"
+base07: "b4befe"
+base08: "f38ba8"
+base09: "fab387"
+base0A: "f9e2af"
+base0B: "a6e3a1"
+base0C: "94e2d5"
+base0D: "89b4fa"
+base0E: "cba6f7"
+base0F: "f2cdcd" # flamingo
+`)
+	write("Palette.yml", `system: "base16"
+name: "Palette"
+palette:
+  base01: "#111111"
+  base02: "#222222"
+  base03: "#333333"
+  base04: "#444444"
+  base05: "#555555"
+  base08: "#880000"
+  base09: "#990000"
+  base0A: "#aa0000"
+  base0B: "#00bb00"
+  base0C: "#0000cc"
+  base0D: "#0000dd"
+  base0E: "#ee00ee"
+  base0F: "#ff00ff"
+`)
+	write("partial.json", `{"name": "partial", "base": {"text": "#ffffff"}}`)
+	write("typo.json", `{"name": "typo", "colours": {}}`)
+	write("badcolor.json", strings.Replace(string(full), Nord.Base.Red, "reddish", 1))
+	write("short.yaml", "base05: ffffff\n")
+	write("notes.txt", "ignored")
+
+	got, err := LoadUserThemes(dir)
+	var names []string
+	for _, th := range got {
+		names = append(names, th.Name)
+	}
+	// Files load in directory order; base16 themes are named after the file.
+	if strings.Join(names, ",") != "palette,acme,flat" {
+		t.Fatalf("loaded %v, want palette, acme, flat", names)
+	}
+	if got[2].Base.Text != "#cdd6f4" || got[2].Base.Pink != "#f2cdcd" || got[0].Base.Primary != "#0000dd" {
+		t.Fatalf("base16 mapping wrong: %+v / %+v", got[2].Base, got[0].Base)
+	}
+	if err == nil {
+		t.Fatal("expected errors for invalid files")
+	}
+	for _, want := range []string{
+		"partial.json: missing colors: blue, green, muted",
+		"typo.json: invalid JSON",
+		`badcolor.json: invalid colors (use #rrggbb or 0-255): red "reddish"`,
+		"short.yaml: missing base16 colors: base01",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("error %q missing %q", err, want)
+		}
+	}
+
+	if themes, err := LoadUserThemes(filepath.Join(dir, "missing")); themes != nil || err != nil {
+		t.Fatalf("missing dir = %v, %v; want nothing", themes, err)
+	}
+
+	RegisterThemes(got)
+	defer RegisterThemes(nil)
+	if LookupTheme("Palette").Base.Text != "#555555" {
+		t.Fatal("LookupTheme did not find a user theme")
+	}
+	last := AllThemes[len(AllThemes)-1].Name
+	if next := NextThemeName(last); next != "palette" {
+		t.Fatalf("NextThemeName(%q) = %q, want palette", last, next)
+	}
+	if next := NextThemeName("flat"); next != AllThemes[0].Name {
+		t.Fatalf("NextThemeName(flat) = %q, want wrap to %q", next, AllThemes[0].Name)
+	}
+
+	RegisterThemes([]Theme{{Name: "nord", Base: Dracula.Base}})
+	if LookupTheme("nord").Base != Dracula.Base || len(Themes()) != len(AllThemes) {
+		t.Fatal("user theme should replace the built-in of the same name")
+	}
+}
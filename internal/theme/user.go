@@ -0,0 +1,256 @@
+package theme
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// themes is the list LookupTheme and NextThemeName search: the built-ins
+// followed by any user themes added with RegisterThemes.
+var themes = AllThemes
+
+// Themes returns the built-in and registered user themes.
+func Themes() []Theme {
+	return themes
+}
+
+// GetThemesDir returns ~/.habitui/themes, where user themes live.
+func GetThemesDir() string {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "themes"
+	}
+	return filepath.Join(homeDir, ".habitui", "themes")
+}
+
+// RegisterThemes appends user themes to the built-ins. A user theme with a
+// built-in's name replaces it, so a shared palette can also patch one.
+func RegisterThemes(user []Theme) {
+	merged := make([]Theme, 0, len(AllThemes)+len(user))
+	merged = append(merged, AllThemes...)
+	for _, t := range user {
+		replaced := false
+		for i := range merged {
+			if merged[i].Name == t.Name {
+				merged[i] = t
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			merged = append(merged, t)
+		}
+	}
+	themes = merged
+}
+
+// LoadUserThemes reads every *.json and base16 *.yaml/*.yml theme in dir.
+// A missing directory is not an error. Invalid files are skipped and reported
+// together, one line per file, alongside the themes that did load.
+func LoadUserThemes(dir string) ([]Theme, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var loaded []Theme
+	var problems []error
+	seen := make(map[string]string)
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		var parse func([]byte, string) (Theme, error)
+		switch strings.ToLower(filepath.Ext(e.Name())) {
+		case ".json":
+			parse = parseJSONTheme
+		case ".yaml", ".yml":
+			parse = parseBase16Theme
+		default:
+			continue
+		}
+		path := filepath.Join(dir, e.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			problems = append(problems, err)
+			continue
+		}
+		t, err := parse(data, themeNameFromFile(e.Name()))
+		if err == nil {
+			err = validateTheme(t)
+		}
+		if err == nil && seen[t.Name] != "" {
+			err = fmt.Errorf("theme %q is already defined in %s", t.Name, seen[t.Name])
+		}
+		if err != nil {
+			problems = append(problems, fmt.Errorf("%s: %w", path, err))
+			continue
+		}
+		seen[t.Name] = e.Name()
+		loaded = append(loaded, t)
+	}
+	return loaded, errors.Join(problems...)
+}
+
+func themeNameFromFile(name string) string {
+	return normalizeThemeName(strings.TrimSuffix(name, filepath.Ext(name)))
+}
+
+// parseJSONTheme reads a file shaped like Theme: {"name": ..., "base": {...}}.
+// The name defaults to the file name; unknown keys are rejected to catch typos.
+func parseJSONTheme(data []byte, fallbackName string) (Theme, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	var t Theme
+	if err := dec.Decode(&t); err != nil {
+		return Theme{}, fmt.Errorf("invalid JSON: %w", err)
+	}
+	t.Name = normalizeThemeName(t.Name)
+	if t.Name == "" {
+		t.Name = fallbackName
+	}
+	return t, nil
+}
+
+// base16Slots maps base16 palette entries onto our semantic slots. Base16
+// has no pink, so base0F (usually brown or magenta) stands in for it.
+var base16Slots = []struct {
+	key string
+	dst func(*BaseColors) *string
+}{
+	{"base01", func(b *BaseColors) *string { return &b.SurfaceAlt }},
+	{"base02", func(b *BaseColors) *string { return &b.Surface }},
+	{"base03", func(b *BaseColors) *string { return &b.Muted }},
+	{"base04", func(b *BaseColors) *string { return &b.Subtext }},
+	{"base05", func(b *BaseColors) *string { return &b.Text }},
+	{"base08", func(b *BaseColors) *string { return &b.Red }},
+	{"base09", func(b *BaseColors) *string { return &b.Orange }},
+	{"base0a", func(b *BaseColors) *string { return &b.Yellow }},
+	{"base0b", func(b *BaseColors) *string { return &b.Green }},
+	{"base0c", func(b *BaseColors) *string { return &b.Blue }},
+	{"base0d", func(b *BaseColors) *string { return &b.Primary }},
+	{"base0e", func(b *BaseColors) *string { return &b.Purple }},
+	{"base0f", func(b *BaseColors) *string { return &b.Pink }},
+}
+
+// parseBase16Theme reads a base16 scheme, either the classic flat layout
+// (base00: "1e1e2e") or the newer one with a palette: section and #-prefixed
+// values. Only "key: value" lines are understood, which is all schemes use.
+// Scheme names are free text, so the theme is named after the file.
+func parseBase16Theme(data []byte, name string) (Theme, error) {
+	values := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || line == "---" {
+			continue
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return Theme{}, fmt.Errorf("line %d: expected \"key: value\"", lineNo)
+		}
+		values[strings.ToLower(strings.TrimSpace(key))] = yamlScalar(value)
+	}
+	if err := scanner.Err(); err != nil {
+		return Theme{}, err
+	}
+
+	t := Theme{Name: name}
+	var missing []string
+	for _, slot := range base16Slots {
+		v := values[slot.key]
+		if v == "" {
+			missing = append(missing, slot.key)
+			continue
+		}
+		if !strings.HasPrefix(v, "#") {
+			v = "#" + v
+		}
+		*slot.dst(&t.Base) = strings.ToLower(v)
+	}
+	if len(missing) > 0 {
+		return Theme{}, fmt.Errorf("missing base16 colors: %s", strings.Join(missing, ", "))
+	}
+	return t, nil
+}
+
+// yamlScalar unquotes a plain or quoted value and drops a trailing comment.
+func yamlScalar(value string) string {
+	value = strings.TrimSpace(value)
+	if value != "" && (value[0] == '"' || value[0] == '\'') {
+		if end := strings.IndexByte(value[1:], value[0]); end >= 0 {
+			return value[1 : end+1]
+		}
+	}
+	if i := strings.Index(value, " #"); i >= 0 {
+		value = value[:i]
+	}
+	return strings.TrimSpace(value)
+}
+
+var hexColor = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// validColor accepts what lipgloss.Color renders reliably: hex and ANSI 0-255.
+func validColor(c string) bool {
+	if hexColor.MatchString(c) {
+		return true
+	}
+	n, err := strconv.Atoi(c)
+	return err == nil && n >= 0 && n <= 255
+}
+
+// validateTheme requires a name and a valid color in every slot, so a user
+// theme can't leave parts of the UI unstyled.
+func validateTheme(t Theme) error {
+	if t.Name == "" {
+		return errors.New("theme has no name")
+	}
+	slots := map[string]string{
+		"text":        t.Base.Text,
+		"subtext":     t.Base.Subtext,
+		"muted":       t.Base.Muted,
+		"surface":     t.Base.Surface,
+		"surface_alt": t.Base.SurfaceAlt,
+		"primary":     t.Base.Primary,
+		"red":         t.Base.Red,
+		"orange":      t.Base.Orange,
+		"yellow":      t.Base.Yellow,
+		"green":       t.Base.Green,
+		"blue":        t.Base.Blue,
+		"purple":      t.Base.Purple,
+		"pink":        t.Base.Pink,
+	}
+	var missing, invalid []string
+	for slot, c := range slots {
+		switch {
+		case c == "":
+			missing = append(missing, slot)
+		case !validColor(c):
+			invalid = append(invalid, fmt.Sprintf("%s %q", slot, c))
+		}
+	}
+	sort.Strings(missing)
+	sort.Strings(invalid)
+	var problems []string
+	if len(missing) > 0 {
+		problems = append(problems, "missing colors: "+strings.Join(missing, ", "))
+	}
+	if len(invalid) > 0 {
+		problems = append(problems, "invalid colors (use #rrggbb or 0-255): "+strings.Join(invalid, ", "))
+	}
+	if len(problems) > 0 {
+		return errors.New(strings.Join(problems, "; "))
+	}
+	return nil
+}
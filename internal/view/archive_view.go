@@ -0,0 +1,239 @@
+package view
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/bShaak/habitui/internal/models"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// archiveSelectedHabit hides the selected habit from the active list; its history is kept.
+func archiveSelectedHabit(m Model) Model {
+	if len(m.habits) == 0 {
+		return m
+	}
+	habit := m.habits[m.cursor]
+	if err := m.store.ArchiveHabit(context.Background(), habit.ID); err != nil {
+		log.Printf("Error archiving habit: %s", err)
+		m.setError("Could not archive habit")
+		return m
+	}
+	m = removeHabitAt(m, m.cursor)
+	m.setStatus(fmt.Sprintf("Archived %s  (%s: view archive)", habit.Label(), helpKey(keys.Archived)))
+	return m
+}
+
+func openArchive(m Model) Model {
+	m.statusMsg = ""
+	m = loadArchive(m)
+	m.archiveCursor = 0
+	m.scrollOffset = 0
+	m.screen = screenArchive
+	return m
+}
+
+// archiveSummary is an archived habit's history at a glance, computed once
+// when the archive is loaded.
+type archiveSummary struct {
+	checkIns    int
+	bestStreak  int
+	first, last string // DayLayout; empty without check-ins
+}
+
+// summarizeArchive summarizes habits from one query over their daily totals.
+func summarizeArchive(m Model, habits []models.Habit) []archiveSummary {
+	if len(habits) == 0 {
+		return nil
+	}
+	ids := make([]int64, len(habits))
+	for i, h := range habits {
+		ids[i] = h.ID
+	}
+	now := today()
+	totals, err := m.store.GetDailyTotalsByHabitIDs(context.Background(), ids, time.Time{}, now)
+	if err != nil {
+		log.Printf("Error fetching archived habit history: %s", err)
+	}
+	history := historyFromTotals(totals)
+	summaries := make([]archiveSummary, len(habits))
+	for i, h := range habits {
+		sum := &summaries[i]
+		sum.checkIns, _ = history.totalsInRange(h, time.Time{}, now)
+		_, sum.bestStreak = streaksFromDays(h, history.amounts(h), m.pauses, now)
+		for day := range history[h.ID] {
+			if sum.first == "" || day < sum.first {
+				sum.first = day
+			}
+			if day > sum.last {
+				sum.last = day
+			}
+		}
+	}
+	return summaries
+}
+
+func loadArchive(m Model) Model {
+	archived, err := m.store.ListArchivedHabits(context.Background())
+	if err != nil {
+		log.Printf("Error fetching archived habits: %s", err)
+	}
+	m.archivedHabits = archived
+	m.archiveSummaries = summarizeArchive(m, archived)
+	if m.archiveCursor >= len(m.archivedHabits) {
+		m.archiveCursor = max(len(m.archivedHabits)-1, 0)
+	}
+	return m
+}
+
+func updateArchive(m Model, msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if m.confirmingDelete {
+			switch {
+			case key.Matches(msg, keys.Confirm):
+				return deleteArchivedHabit(m), nil
+			case key.Matches(msg, keys.Cancel, keys.Delete):
+				m.confirmingDelete = false
+			}
+			return m, nil
+		}
+
+		switch {
+		case key.Matches(msg, keys.Quit):
+			return m, tea.Quit
+		case key.Matches(msg, keys.Up):
+			if m.archiveCursor > 0 {
+				m.archiveCursor--
+			}
+		case key.Matches(msg, keys.Down):
+			if m.archiveCursor < len(m.archivedHabits)-1 {
+				m.archiveCursor++
+			}
+		case key.Matches(msg, keys.Details):
+			return openHabitDetail(m, screenArchive), nil
+		case key.Matches(msg, keys.Restore):
+			return restoreArchivedHabit(m), nil
+		case key.Matches(msg, keys.Delete):
+			if len(m.archivedHabits) > 0 {
+				m.confirmingDelete = true
+			}
+		}
+	}
+	return m, nil
+}
+
+func restoreArchivedHabit(m Model) Model {
+	if len(m.archivedHabits) == 0 {
+		return m
+	}
+	habit := m.archivedHabits[m.archiveCursor]
+	if err := m.store.UnarchiveHabit(context.Background(), habit.ID); err != nil {
+		log.Printf("Error restoring habit: %s", err)
+		m.setError("Could not restore habit")
+		return m
+	}
+	m = refreshStreakCompletions(m)
+	m = reloadHabits(m)
+	m = loadArchive(m)
+	m.setStatus("Restored " + habit.Label())
+	return m
+}
+
+func deleteArchivedHabit(m Model) Model {
+	m.confirmingDelete = false
+	if len(m.archivedHabits) == 0 {
+		return m
+	}
+	habit := m.archivedHabits[m.archiveCursor]
+	if err := m.store.DeleteHabit(context.Background(), habit.ID); err != nil {
+		log.Printf("Error deleting habit: %s", err)
+		m.setError("Could not delete habit")
+		return m
+	}
+	m = loadArchive(m)
+	m.statusMsg = ""
+	return m
+}
+
+func formatArchiveDate(ts string) string {
+	t, err := time.Parse(time.RFC3339, ts)
+	if err != nil {
+		return "unknown"
+	}
+	return t.In(time.Local).Format("Jan 2, 2006")
+}
+
+// formatArchiveDay formats a DayLayout day like formatArchiveDate.
+func formatArchiveDay(day string) string {
+	d, err := time.Parse(models.DayLayout, day)
+	if err != nil {
+		return day
+	}
+	return d.Format("Jan 2, 2006")
+}
+
+func viewArchive(m Model) string {
+	s := m.styles
+	var b strings.Builder
+	b.WriteString(m.renderTitle())
+	b.WriteString("\n")
+	b.WriteString(m.appBoundaryView("Archived Habits"))
+	b.WriteString("\n\n")
+
+	labelStyle := lipgloss.NewStyle().Foreground(subtext)
+	var content strings.Builder
+	if len(m.archivedHabits) == 0 {
+		content.WriteString(s.Help.Render(fmt.Sprintf("No archived habits.\n\nPress '%s' on the main view to archive one.", helpKey(keys.Archive))))
+		content.WriteString("\n\n")
+	} else {
+		for i, h := range m.archivedHabits {
+			cursor := " "
+			if i == m.archiveCursor {
+				cursor = ">"
+			}
+			nameStyle := lipgloss.NewStyle().Foreground(getHabitColor(h.Color))
+			if i == m.archiveCursor {
+				nameStyle = nameStyle.Bold(true)
+			}
+			content.WriteString(fmt.Sprintf("%s %s %s\n",
+				cursor,
+				nameStyle.Render(h.Label()),
+				labelStyle.Render("archived "+formatArchiveDate(h.ArchivedAt)),
+			))
+
+			var sum archiveSummary
+			if i < len(m.archiveSummaries) {
+				sum = m.archiveSummaries[i]
+			}
+			summary := fmt.Sprintf("    %d check-ins  |  best streak %d days", sum.checkIns, sum.bestStreak)
+			if sum.first != "" {
+				summary += fmt.Sprintf("  |  %s – %s", formatArchiveDay(sum.first), formatArchiveDay(sum.last))
+			}
+			content.WriteString(labelStyle.Render(summary))
+			content.WriteString("\n")
+		}
+		content.WriteString("\n")
+	}
+
+	if m.confirmingDelete && len(m.archivedHabits) > 0 {
+		habitName := m.archivedHabits[m.archiveCursor].Label()
+		content.WriteString(lipgloss.NewStyle().Foreground(red).Bold(true).Render(
+			fmt.Sprintf("Permanently delete %q and its history?  %s", habitName, confirmHelp()),
+		))
+	} else {
+		if m.statusMsg != "" {
+			content.WriteString(statusStyle(m.statusInfo).Render(m.statusMsg))
+			content.WriteString("\n")
+		}
+		content.WriteString(m.shortHelp())
+	}
+
+	b.WriteString(s.ContentBox.Render(content.String()))
+	return s.Base.Render(b.String())
+}
@@ -6,39 +6,56 @@ import (
 	"log"
 	"strings"
 
+	"github.com/bShaak/habitui/internal/models"
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
 )
 
 var (
 	cellWidth      = 8
 	habitNameWidth = 12
-	dayNames       = []string{"Mon", "Tue", "Wed", "Thu", "Fri", "Sat", "Sun"}
 )
 
+// calendarProgress is a quantity habit's cell, e.g. "18/30", or only the
+// amount when the target doesn't fit as well.
+func calendarProgress(habit models.Habit, amount float64) string {
+	progress := models.FormatAmount(amount) + "/" + models.FormatAmount(habit.DailyTarget())
+	if runewidth.StringWidth(progress) > cellWidth {
+		return runewidth.Truncate(models.FormatAmount(amount), cellWidth, "…")
+	}
+	return progress
+}
+
 func updateCalendar(m Model, msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "q":
+		if m.searching {
+			return updateSearch(m, msg)
+		}
+		switch {
+		case key.Matches(msg, keys.Quit):
 			return m, tea.Quit
-		case "j":
+		case key.Matches(msg, keys.Search):
+			return startSearch(m)
+		case key.Matches(msg, keys.Down):
 			if len(m.habits) > 0 && m.cursor < len(m.habits)-1 {
 				m.cursor++
 			}
-		case "k":
+		case key.Matches(msg, keys.Up):
 			if m.cursor > 0 {
 				m.cursor--
 			}
-		case "l":
+		case key.Matches(msg, keys.Right):
 			if m.calendarCol < 6 {
 				m.calendarCol++
 			}
-		case "h":
+		case key.Matches(msg, keys.Left):
 			if m.calendarCol > 0 {
 				m.calendarCol--
 			}
-		case "L":
+		case key.Matches(msg, keys.Next):
 			m.weekStart = m.weekStart.AddDate(0, 0, 7)
 			weekEnd := m.weekStart.AddDate(0, 0, 6)
 			completions, err := m.store.GetCompletionsByDateRange(context.Background(), m.weekStart, weekEnd)
@@ -47,7 +64,7 @@ func updateCalendar(m Model, msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, nil
 			}
 			m.weekCompletions = completions
-		case "H":
+		case key.Matches(msg, keys.Prev):
 			m.weekStart = m.weekStart.AddDate(0, 0, -7)
 			weekEnd := m.weekStart.AddDate(0, 0, 6)
 			completions, err := m.store.GetCompletionsByDateRange(context.Background(), m.weekStart, weekEnd)
@@ -56,25 +73,68 @@ func updateCalendar(m Model, msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, nil
 			}
 			m.weekCompletions = completions
-		case "enter":
+		case key.Matches(msg, keys.TagFilter):
+			return cycleTagFilter(m), nil
+		case key.Matches(msg, keys.SkipHabit):
 			if len(m.habits) == 0 {
 				return m, nil
 			}
-			selectedHabit := m.habits[m.cursor]
-			selectedDate := m.weekStart.AddDate(0, 0, m.calendarCol)
-			updated, err := m.toggleDayCompletion(selectedHabit, selectedDate, m.weekCompletions)
-			if err != nil {
-				log.Printf("Error toggling completion: %s", err)
-				return m, nil
+			return toggleSkip(m, m.habits[m.cursor].ID, m.weekStart.AddDate(0, 0, m.calendarCol)), nil
+		case key.Matches(msg, keys.SkipAll):
+			return toggleSkip(m, 0, m.weekStart.AddDate(0, 0, m.calendarCol)), nil
+		case key.Matches(msg, keys.Heatmap):
+			if len(m.habits) == 0 {
+				return openHeatmap(m, 0), nil
 			}
-			m.weekCompletions = updated
+			return openHeatmap(m, m.cursor+1), nil
+		case key.Matches(msg, keys.Toggle):
+			return toggleCalendarCell(m)
+		case key.Matches(msg, keys.DayLog):
+			var habitID int64
+			if len(m.habits) > 0 {
+				habitID = m.habits[m.cursor].ID
+			}
+			return openDayLog(m, m.weekStart.AddDate(0, 0, m.calendarCol), screenCalendar, habitID), nil
+		case key.Matches(msg, keys.Details):
+			return openHabitDetail(m, screenCalendar), nil
+		case key.Matches(msg, keys.Increment):
+			return stepSelectedHabit(m, 1)
+		case key.Matches(msg, keys.Decrement):
+			return stepSelectedHabit(m, -1)
 		}
 	}
 	return m, nil
 }
 
+// toggleCalendarCell toggles the selected habit on the selected day.
+func toggleCalendarCell(m Model) (tea.Model, tea.Cmd) {
+	if len(m.habits) == 0 {
+		return m, nil
+	}
+	selectedHabit := m.habits[m.cursor]
+	selectedDate := m.weekStart.AddDate(0, 0, m.calendarCol)
+	if selectedHabit.IsQuantity() {
+		return openAmountEntry(m, selectedHabit, selectedDate, screenCalendar)
+	}
+	updated, err := m.toggleDayCompletion(selectedHabit, selectedDate, m.weekCompletions)
+	if err != nil {
+		log.Printf("Error toggling completion: %s", err)
+		return m, nil
+	}
+	m.weekCompletions = updated
+	return m, nil
+}
+
 func viewCalendar(m Model) string {
+	v, _ := renderCalendar(m)
+	return v
+}
+
+// renderCalendar draws the week grid and records a zone for each habit name
+// and day cell.
+func renderCalendar(m Model) (string, layout) {
 	s := m.styles
+	var l layout
 	var b strings.Builder
 	b.WriteString(m.renderTitle())
 	b.WriteString("\n")
@@ -84,11 +144,15 @@ func viewCalendar(m Model) string {
 		m.weekStart.Format("Jan"), m.weekStart.Day(),
 		weekEnd.Format("Jan"), weekEnd.Day(),
 		m.weekStart.Year())
-	header := m.appBoundaryView(headerText)
+	header := m.appBoundaryView(m.withTagFilter(headerText))
 	b.WriteString(header)
 	b.WriteString("\n\n")
 
 	var content strings.Builder
+	if m.searchActive() {
+		content.WriteString(m.viewSearchLine())
+		content.WriteString("\n\n")
+	}
 
 	headerCell := lipgloss.NewStyle().
 		Foreground(blue).
@@ -101,7 +165,7 @@ func viewCalendar(m Model) string {
 
 	for i := 0; i < 7; i++ {
 		date := m.weekStart.AddDate(0, 0, i)
-		dayHeader := fmt.Sprintf("%s %d", dayNames[i], date.Day())
+		dayHeader := fmt.Sprintf("%s %d", date.Format("Mon"), date.Day())
 		content.WriteString(headerCell.Render(dayHeader))
 	}
 	content.WriteString("\n")
@@ -116,8 +180,10 @@ func viewCalendar(m Model) string {
 	}
 	content.WriteString("\n")
 
-	if len(m.habits) == 0 {
-		content.WriteString(s.Help.Render("No habits created yet.\n\nPress 'a' from main view to create a new one."))
+	if len(m.habits) == 0 && m.searchActive() {
+		content.WriteString(s.Help.Render("No habits match."))
+	} else if len(m.habits) == 0 {
+		content.WriteString(s.Help.Render(fmt.Sprintf("No habits created yet.\n\nPress '%s' from main view to create a new one.", helpKey(keys.Add))))
 	} else {
 		for row, habit := range m.habits {
 			habitColor := getHabitColor(habit.Color)
@@ -125,28 +191,21 @@ func viewCalendar(m Model) string {
 			if row == m.cursor {
 				nameStyle = nameStyle.Bold(true)
 			}
-			name := formatHabitLabel(habit)
-			if len([]rune(name)) > habitNameWidth-1 {
-				runes := []rune(name)
-				name = string(runes[:habitNameWidth-2]) + "…"
-			}
+			name := runewidth.Truncate(habit.Label(), habitNameWidth-1, "…")
+			l.mark(&content, zoneHabit, row, 0, habitNameWidth)
 			content.WriteString(nameStyle.Render(name))
 
-			frequencyDays := parseFrequency(habit.Frequency)
-
 			for col := 0; col < 7; col++ {
 				date := m.weekStart.AddDate(0, 0, col)
-				dayName := getDayName(date)
-				completionCount := getCompletionsForHabitAndDate(m.weekCompletions, habit.ID, date)
+				amount := getAmountForHabitAndDate(m.weekCompletions, habit, date)
 
 				var cellContent string
 				var cellStyle lipgloss.Style
 
-				isScheduled := isScheduledOnDay(habit.Frequency, dayName)
-				isComplete := completionCount >= effectiveGoal(habit.Goal)
-				isPartial := completionCount > 0 && completionCount < effectiveGoal(habit.Goal)
-				_, frequencyHasSpecificDays := frequencyDays[getDayName(date)]
-				hasSpecificDays := len(frequencyDays) > 0 && frequencyHasSpecificDays
+				isScheduled := isDueOn(habit, m.weekCompletions, m.pauses, date)
+				isComplete := habit.TargetMet(amount)
+				isPartial := amount > 0 && !isComplete
+				paused, _ := pauseGlyph(m.pauses, habit.ID, date)
 
 				cellStyle = lipgloss.NewStyle().Width(cellWidth).Align(lipgloss.Center)
 
@@ -176,7 +235,12 @@ func viewCalendar(m Model) string {
 					if !(row == m.cursor && col == m.calendarCol) {
 						cellStyle = cellStyle.Foreground(yellow)
 					}
-				} else if isScheduled || hasSpecificDays {
+				} else if paused != "" {
+					cellContent = paused
+					if !(row == m.cursor && col == m.calendarCol) {
+						cellStyle = cellStyle.Foreground(blue)
+					}
+				} else if isScheduled {
 					cellContent = "·"
 					if !(row == m.cursor && col == m.calendarCol) {
 						cellStyle = cellStyle.Foreground(muted)
@@ -187,7 +251,11 @@ func viewCalendar(m Model) string {
 						cellStyle = cellStyle.Foreground(muted)
 					}
 				}
+				if habit.IsQuantity() && (isComplete || isPartial) {
+					cellContent = calendarProgress(habit, amount)
+				}
 
+				l.mark(&content, zoneDay, row, col, cellWidth)
 				content.WriteString(cellStyle.Render(cellContent))
 			}
 			content.WriteString("\n")
@@ -195,11 +263,13 @@ func viewCalendar(m Model) string {
 	}
 
 	content.WriteString("\n")
-	helpText := "h/l: navigate days  |  j/k: navigate habits  |  enter: toggle  |  H/L: prev/next week  |  esc: back  |  q: quit"
-	help := s.Help.Render(helpText)
-	content.WriteString(help)
+	content.WriteString(s.Help.Render(fmt.Sprintf("✓ done  · due  %s skipped  %s vacation", skipGlyph, vacationGlyph)))
+	content.WriteString("\n")
+	content.WriteString(m.shortHelp())
 
+	var frame layout
+	frame.embedBox(s, b.String(), l)
 	b.WriteString(s.ContentBox.Render(content.String()))
 
-	return s.Base.Render(b.String())
+	return s.Base.Render(b.String()), frame.framed(s)
 }
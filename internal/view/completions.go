@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/bShaak/habitui/internal/models"
+	tea "github.com/charmbracelet/bubbletea"
 )
 
 // toggleDayCompletion adds a completion for habit on day, or removes all completions that day if the goal is already met.
@@ -14,48 +15,136 @@ func (m Model) toggleDayCompletion(habit models.Habit, day time.Time, list []mod
 	count := getCompletionsForHabitAndDate(list, habit.ID, day)
 
 	if count >= goal {
-		completions, err := m.store.GetCompletionsByHabitID(context.Background(), habit.ID)
-		if err != nil {
-			return list, err
-		}
-		for _, c := range completions {
-			completedAt, err := time.Parse(time.RFC3339, c.CompletedAt)
-			if err != nil {
-				continue
-			}
-			if !inDayBounds(completedAt, day) {
-				continue
-			}
-			if err := m.store.DeleteCompletion(context.Background(), c.ID); err != nil {
-				log.Printf("Error deleting completion: %s", err)
-			}
+		return m.clearDayCompletions(habit, day, list)
+	}
+	return m.addDayCompletion(habit, day, list)
+}
+
+// addDayCompletion records one more check-in for habit on day, even past its goal.
+func (m Model) addDayCompletion(habit models.Habit, day time.Time, list []models.Completion) ([]models.Completion, error) {
+	completedAt := models.CompletionTimeOnDay(day, time.Now())
+	c, err := m.store.CreateCompletion(context.Background(), &models.Completion{
+		HabitID:     habit.ID,
+		CompletedAt: completedAt.Format(time.RFC3339),
+	})
+	if err != nil {
+		return list, err
+	}
+	return append(list, *c), nil
+}
+
+// removeLastDayCompletion deletes the most recent completion habit has on day
+// and drops it from list. It reports false when there was nothing to remove.
+func (m Model) removeLastDayCompletion(habit models.Habit, day time.Time, list []models.Completion) ([]models.Completion, bool, error) {
+	completions, err := m.store.GetCompletionsByHabitIDAndDate(context.Background(), habit.ID, day)
+	if err != nil || len(completions) == 0 {
+		return list, false, err
+	}
+	latest := completions[0]
+	for _, c := range completions[1:] {
+		if completedAfter(c, latest) {
+			latest = c
 		}
-		var updated []models.Completion
-		for _, c := range list {
-			completedAt, err := time.Parse(time.RFC3339, c.CompletedAt)
-			if err != nil {
-				updated = append(updated, c)
-				continue
-			}
-			if c.HabitID == habit.ID && inDayBounds(completedAt, day) {
-				continue
-			}
+	}
+	if err := m.store.DeleteCompletion(context.Background(), latest.ID); err != nil {
+		return list, false, err
+	}
+	var updated []models.Completion
+	for _, c := range list {
+		if c.ID != latest.ID {
 			updated = append(updated, c)
 		}
-		return updated, nil
 	}
+	return updated, true, nil
+}
+
+// completedAfter orders completions by time, then by ID for equal times.
+func completedAfter(a, b models.Completion) bool {
+	at, errA := time.Parse(time.RFC3339, a.CompletedAt)
+	bt, errB := time.Parse(time.RFC3339, b.CompletedAt)
+	if errA == nil && errB == nil && !at.Equal(bt) {
+		return at.After(bt)
+	}
+	return a.ID > b.ID
+}
 
-	now := time.Now()
-	completedAt := time.Date(
-		day.Year(), day.Month(), day.Day(),
-		now.Hour(), now.Minute(), now.Second(), 0, now.Location(),
+// stepSelectedHabit adds one check-in (delta > 0) or removes the latest one
+// for the selected habit: today on the main screen, the selected cell on the
+// calendar. On quantity habits + logs one unit.
+func stepSelectedHabit(m Model, delta int) (tea.Model, tea.Cmd) {
+	if len(m.habits) == 0 {
+		return m, nil
+	}
+	habit := m.habits[m.cursor]
+	day, from := today(), screenMain
+	if m.screen == screenCalendar {
+		day, from = m.weekStart.AddDate(0, 0, m.calendarCol), screenCalendar
+	}
+	list := m.completionsFor(from)
+	var (
+		updated []models.Completion
+		err     error
 	)
+	if delta > 0 {
+		updated, err = m.addDayCompletion(habit, day, list)
+	} else {
+		var removed bool
+		updated, removed, err = m.removeLastDayCompletion(habit, day, list)
+		if err == nil && !removed {
+			m.setError("Nothing to remove for " + habit.Label())
+			return m, nil
+		}
+	}
+	if err != nil {
+		log.Printf("Error updating check-ins: %s", err)
+		m.setError("Could not update check-ins")
+		return m, nil
+	}
+	if from == screenCalendar {
+		m.weekCompletions = updated
+	} else {
+		m.completions = updated
+	}
+	m.setStatus(habit.Label() + ": " + habit.FormatProgress(getAmountForHabitAndDate(updated, habit, day)))
+	m = refreshStreakCompletions(m)
+	return arrangeHabits(m), nil
+}
+
+// logDayAmount records value toward a quantity habit on day. A zero value clears the day.
+func (m Model) logDayAmount(habit models.Habit, day time.Time, value float64, list []models.Completion) ([]models.Completion, error) {
+	if value <= 0 {
+		return m.clearDayCompletions(habit, day, list)
+	}
+	completedAt := models.CompletionTimeOnDay(day, time.Now())
 	c, err := m.store.CreateCompletion(context.Background(), &models.Completion{
 		HabitID:     habit.ID,
 		CompletedAt: completedAt.Format(time.RFC3339),
+		Value:       value,
 	})
 	if err != nil {
 		return list, err
 	}
 	return append(list, *c), nil
 }
+
+// clearDayCompletions deletes every completion habit has on day and drops them from list.
+func (m Model) clearDayCompletions(habit models.Habit, day time.Time, list []models.Completion) ([]models.Completion, error) {
+	completions, err := m.store.GetCompletionsByHabitIDAndDate(context.Background(), habit.ID, day)
+	if err != nil {
+		return list, err
+	}
+	for _, c := range completions {
+		if err := m.store.DeleteCompletion(context.Background(), c.ID); err != nil {
+			log.Printf("Error deleting completion: %s", err)
+		}
+	}
+	key := models.DayKey(day)
+	var updated []models.Completion
+	for _, c := range list {
+		if c.HabitID == habit.ID && c.Day() == key {
+			continue
+		}
+		updated = append(updated, c)
+	}
+	return updated, nil
+}
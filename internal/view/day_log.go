@@ -0,0 +1,486 @@
+package view
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bShaak/habitui/internal/models"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// dayLogState is the day log screen: every check-in on one day, in time order.
+type dayLogState struct {
+	day     time.Time
+	from    screen // where esc returns
+	entries []models.Completion
+	cursor  int
+}
+
+// dayLogFields backs the add/edit check-in form. entry is nil when adding.
+type dayLogFields struct {
+	entry   *models.Completion
+	HabitID int64
+	Time    string
+	Amount  string
+}
+
+// parseClock reads an "HH:MM" time of day.
+func parseClock(str string) (hour, minute int, err error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(str))
+	if err != nil {
+		return 0, 0, errors.New("use HH:MM, e.g. 07:30")
+	}
+	return t.Hour(), t.Minute(), nil
+}
+
+// completionTime is a completion's moment in local time.
+func completionTime(c models.Completion) (time.Time, bool) {
+	t, err := time.Parse(time.RFC3339, c.CompletedAt)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t.In(time.Local), true
+}
+
+// activeHabit finds an active habit by ID.
+func (m Model) activeHabit(id int64) (models.Habit, bool) {
+	for _, h := range m.allHabits {
+		if h.ID == id {
+			return h, true
+		}
+	}
+	return models.Habit{}, false
+}
+
+func buildDayLogForm(m Model, fields *dayLogFields, day time.Time) *huh.Form {
+	var options []huh.Option[int64]
+	for _, h := range m.allHabits {
+		options = append(options, huh.NewOption(h.Label(), h.ID))
+	}
+	isQuantity := func() bool {
+		h, ok := m.activeHabit(fields.HabitID)
+		return ok && h.IsQuantity()
+	}
+	timeDescription := "Check-in time on " + day.Format("Mon Jan 2")
+	if models.DayRollover() > 0 {
+		timeDescription += fmt.Sprintf("; times before %s go on the next morning", clockLabel(models.DayRollover()))
+	}
+	return huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[int64]().
+				Title("Habit").
+				Key("habit").
+				Options(options...).
+				Value(&fields.HabitID),
+		).WithHideFunc(func() bool { return fields.entry != nil }),
+		huh.NewGroup(
+			huh.NewInput().
+				Title("Time").
+				Description(timeDescription).
+				Key("time").
+				Value(&fields.Time).
+				Validate(func(str string) error {
+					_, _, err := parseClock(str)
+					return err
+				}),
+		),
+		huh.NewGroup(
+			huh.NewInput().
+				TitleFunc(func() string {
+					if h, ok := m.activeHabit(fields.HabitID); ok && h.Unit != "" {
+						return "Amount (" + h.Unit + ")"
+					}
+					return "Amount"
+				}, &fields.HabitID).
+				Key("amount").
+				Value(&fields.Amount).
+				Validate(func(str string) error {
+					v, err := parseAmount(str)
+					if err == nil && v == 0 {
+						err = errors.New("amount must be more than 0")
+					}
+					return err
+				}),
+		).WithHideFunc(func() bool { return !isQuantity() }),
+	).WithWidth(60).WithTheme(formTheme()).WithKeyMap(formKeyMap())
+}
+
+// clockLabel formats a duration after midnight as "HH:MM".
+func clockLabel(d time.Duration) string {
+	return fmt.Sprintf("%02d:%02d", int(d.Hours()), int(d.Minutes())%60)
+}
+
+// openDayLog lists the check-ins on day, with the cursor on habitID's latest
+// one when it has any.
+func openDayLog(m Model, day time.Time, from screen, habitID int64) Model {
+	m.statusMsg = ""
+	m.dayLog = &dayLogState{day: startOfDay(day), from: from}
+	m = loadDayLog(m)
+	m.dayLog.cursor = max(len(m.dayLog.entries)-1, 0)
+	for i, c := range m.dayLog.entries {
+		if c.HabitID == habitID {
+			m.dayLog.cursor = i
+		}
+	}
+	m.scrollOffset = 0
+	m.screen = screenDayLog
+	return m
+}
+
+// loadDayLog fetches the day's check-ins for active habits, oldest first.
+func loadDayLog(m Model) Model {
+	completions, err := m.store.GetCompletionsByDate(context.Background(), m.dayLog.day)
+	if err != nil {
+		log.Printf("Error fetching day log: %s", err)
+		return m
+	}
+	var entries []models.Completion
+	for _, c := range completions {
+		if _, ok := m.activeHabit(c.HabitID); ok {
+			entries = append(entries, c)
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return completedAfter(entries[j], entries[i]) })
+	m.dayLog.entries = entries
+	if m.dayLog.cursor >= len(entries) {
+		m.dayLog.cursor = max(len(entries)-1, 0)
+	}
+	return m
+}
+
+// changeDayLogDay moves the log by delta days, never past today.
+func changeDayLogDay(m Model, delta int) Model {
+	day := m.dayLog.day.AddDate(0, 0, delta)
+	if day.After(today()) {
+		return m
+	}
+	m.dayLog.day = day
+	m.dayLog.cursor = 0
+	m.statusMsg = ""
+	return loadDayLog(m)
+}
+
+// closeDayLog returns to the screen the log was opened from.
+func closeDayLog(m Model) Model {
+	from := screenMain
+	if m.dayLog != nil {
+		from = m.dayLog.from
+	}
+	m.dayLog = nil
+	m.confirmingDelete = false
+	m.scrollOffset = 0
+	m.screen = from
+	return m
+}
+
+// refreshAfterDayLog reloads the cached completion lists after the log
+// changed a check-in, which may be on today or in the calendar's week.
+func refreshAfterDayLog(m Model) Model {
+	m = loadDayLog(m)
+	ctx := context.Background()
+	completions, err := m.store.GetCompletionsByDate(ctx, today())
+	if err != nil {
+		log.Printf("Error fetching today's completions: %s", err)
+	} else {
+		m.completions = completions
+	}
+	week, err := m.store.GetCompletionsByDateRange(ctx, m.weekStart, m.weekStart.AddDate(0, 0, 6))
+	if err != nil {
+		log.Printf("Error fetching week completions: %s", err)
+	} else {
+		m.weekCompletions = week
+	}
+	m = refreshStreakCompletions(m)
+	return arrangeHabits(m)
+}
+
+func openDayLogForm(m Model, entry *models.Completion) (Model, tea.Cmd) {
+	fields := &dayLogFields{entry: entry, Time: time.Now().Format("15:04"), Amount: "1"}
+	if entry != nil {
+		fields.HabitID = entry.HabitID
+		if t, ok := completionTime(*entry); ok {
+			fields.Time = t.Format("15:04")
+		}
+		fields.Amount = models.FormatAmount(entry.Value)
+	} else if len(m.dayLog.entries) > 0 {
+		fields.HabitID = m.dayLog.entries[m.dayLog.cursor].HabitID
+	} else if len(m.habits) > 0 {
+		fields.HabitID = m.habits[m.cursor].ID
+	} else {
+		fields.HabitID = m.allHabits[0].ID
+	}
+	m.dayLogFields = fields
+	m.form = buildDayLogForm(m, fields, m.dayLog.day)
+	applyFormSize(m.form, m.width, m.height)
+	m.statusMsg = ""
+	m.scrollOffset = 0
+	m.screen = screenDayLogEntry
+	return m, m.form.Init()
+}
+
+func closeDayLogForm(m Model) Model {
+	m.form = nil
+	m.dayLogFields = nil
+	m.screen = screenDayLog
+	return m
+}
+
+func updateDayLog(m Model, msg tea.Msg) (tea.Model, tea.Cmd) {
+	if m.dayLog == nil {
+		return closeDayLog(m), nil
+	}
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if m.confirmingDelete {
+			switch {
+			case key.Matches(msg, keys.Confirm):
+				return deleteDayLogEntry(m), nil
+			case key.Matches(msg, keys.Cancel, keys.Delete):
+				m.confirmingDelete = false
+			}
+			return m, nil
+		}
+
+		state := m.dayLog
+		switch {
+		case key.Matches(msg, keys.Quit):
+			return m, tea.Quit
+		case key.Matches(msg, keys.Up):
+			if state.cursor > 0 {
+				state.cursor--
+			}
+		case key.Matches(msg, keys.Down):
+			if state.cursor < len(state.entries)-1 {
+				state.cursor++
+			}
+		case key.Matches(msg, keys.Left):
+			return changeDayLogDay(m, -1), nil
+		case key.Matches(msg, keys.Right):
+			return changeDayLogDay(m, 1), nil
+		case key.Matches(msg, keys.Add):
+			if len(m.allHabits) > 0 {
+				return openDayLogForm(m, nil)
+			}
+		case key.Matches(msg, keys.Edit):
+			if len(state.entries) > 0 {
+				entry := state.entries[state.cursor]
+				return openDayLogForm(m, &entry)
+			}
+		case key.Matches(msg, keys.Delete):
+			if len(state.entries) > 0 {
+				m.confirmingDelete = true
+			}
+		}
+	}
+	return m, nil
+}
+
+func updateDayLogEntry(m Model, msg tea.Msg) (tea.Model, tea.Cmd) {
+	if _, ok := msg.(tea.WindowSizeMsg); ok {
+		applyFormSize(m.form, m.width, m.height)
+		return m, nil
+	}
+	if m.dayLogFields == nil || m.dayLog == nil {
+		return closeDayLogForm(m), nil
+	}
+
+	var cmds []tea.Cmd
+	form, cmd := m.form.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.form = f
+		cmds = append(cmds, cmd)
+	}
+	if m.form.State != huh.StateCompleted {
+		return m, tea.Batch(cmds...)
+	}
+	return saveDayLogEntry(m), nil
+}
+
+// saveDayLogEntry adds or updates the check-in described by the form.
+func saveDayLogEntry(m Model) Model {
+	fields := m.dayLogFields
+	m = closeDayLogForm(m)
+	habit, ok := m.activeHabit(fields.HabitID)
+	if !ok {
+		m.setError("Could not save check-in: habit not found")
+		return m
+	}
+	hour, minute, err := parseClock(fields.Time)
+	if err != nil {
+		m.setError("Could not save check-in: " + err.Error())
+		return m
+	}
+	value := 1.0
+	if habit.IsQuantity() {
+		if value, err = parseAmount(fields.Amount); err != nil || value == 0 {
+			m.setError("Could not save check-in: enter an amount")
+			return m
+		}
+	}
+	day := m.dayLog.day
+	clock := time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, time.Local)
+	completedAt := models.CompletionTimeOnDay(day, clock).Format(time.RFC3339)
+
+	ctx := context.Background()
+	var saved models.Completion
+	if fields.entry == nil {
+		c, err := m.store.CreateCompletion(ctx, &models.Completion{HabitID: habit.ID, CompletedAt: completedAt, Value: value})
+		if err != nil {
+			log.Printf("Error creating completion: %s", err)
+			m.setError("Could not add check-in")
+			return m
+		}
+		saved = *c
+		m.setStatus(fmt.Sprintf("Check-in added: %s at %s", habit.Label(), clock.Format("15:04")))
+	} else {
+		saved = *fields.entry
+		// Keep the seconds when only the amount changed.
+		if t, ok := completionTime(saved); !ok || t.Format("15:04") != clock.Format("15:04") {
+			saved.CompletedAt = completedAt
+		}
+		saved.Value = value
+		if err := m.store.UpdateCompletion(ctx, &saved); err != nil {
+			log.Printf("Error updating completion: %s", err)
+			m.setError("Could not update check-in")
+			return m
+		}
+		m.setStatus(fmt.Sprintf("Check-in updated: %s at %s", habit.Label(), clock.Format("15:04")))
+	}
+
+	m = refreshAfterDayLog(m)
+	for i, c := range m.dayLog.entries {
+		if c.ID == saved.ID {
+			m.dayLog.cursor = i
+		}
+	}
+	return m
+}
+
+func deleteDayLogEntry(m Model) Model {
+	m.confirmingDelete = false
+	if len(m.dayLog.entries) == 0 {
+		return m
+	}
+	c := m.dayLog.entries[m.dayLog.cursor]
+	if err := m.store.DeleteCompletion(context.Background(), c.ID); err != nil {
+		log.Printf("Error deleting completion: %s", err)
+		m.setError("Could not delete check-in")
+		return m
+	}
+	m.statusMsg = ""
+	return refreshAfterDayLog(m)
+}
+
+// formatDayLogEntry describes one check-in: its habit, its time, and the
+// amount for quantity habits. Times past midnight are flagged, since with a
+// later day_ends_at they still belong to the day shown.
+func formatDayLogEntry(m Model, c models.Completion) (clock, label, detail string) {
+	clock = "--:--"
+	if t, ok := completionTime(c); ok {
+		clock = t.Format("15:04")
+		if models.DayKey(t) != models.DayKey(m.dayLog.day) {
+			detail = "(after midnight)"
+		}
+	}
+	habit, _ := m.activeHabit(c.HabitID)
+	label = habit.Label()
+	if habit.IsQuantity() {
+		amount := models.FormatAmount(c.Value)
+		if habit.Unit != "" {
+			amount += " " + habit.Unit
+		}
+		detail = strings.TrimSpace(amount + " " + detail)
+	}
+	return clock, label, detail
+}
+
+func viewDayLog(m Model) string {
+	s := m.styles
+	var b strings.Builder
+	b.WriteString(m.renderTitle())
+	b.WriteString("\n")
+	b.WriteString(m.appBoundaryView("Day Log"))
+	b.WriteString("\n\n")
+
+	var content strings.Builder
+	if m.dayLog == nil {
+		b.WriteString(s.ContentBox.Render(m.shortHelp()))
+		return s.Base.Render(b.String())
+	}
+	dayLabel := m.dayLog.day.Format("Monday, Jan 2, 2006")
+	if m.dayLog.day.Equal(today()) {
+		dayLabel += " (today)"
+	}
+	content.WriteString(s.StatusHeader.Render(dayLabel))
+	content.WriteString("\n\n")
+
+	labelStyle := lipgloss.NewStyle().Foreground(subtext)
+	entries := m.dayLog.entries
+	if len(entries) == 0 {
+		content.WriteString(s.Help.Render(fmt.Sprintf("No check-ins on this day. Press '%s' to add one.", helpKey(keys.Add))))
+		content.WriteString("\n\n")
+	} else {
+		for i, c := range entries {
+			cursor := " "
+			timeStyle := lipgloss.NewStyle().Foreground(blue)
+			habit, _ := m.activeHabit(c.HabitID)
+			nameStyle := lipgloss.NewStyle().Foreground(getHabitColor(habit.Color))
+			if i == m.dayLog.cursor {
+				cursor = ">"
+				timeStyle = timeStyle.Bold(true)
+				nameStyle = nameStyle.Bold(true)
+			}
+			clock, label, detail := formatDayLogEntry(m, c)
+			line := fmt.Sprintf("%s %s  %s", cursor, timeStyle.Render(clock), nameStyle.Render(label))
+			if detail != "" {
+				line += "  " + labelStyle.Render(detail)
+			}
+			content.WriteString(line)
+			content.WriteString("\n")
+		}
+		content.WriteString("\n")
+	}
+
+	if m.confirmingDelete && len(entries) > 0 {
+		clock, label, _ := formatDayLogEntry(m, entries[m.dayLog.cursor])
+		content.WriteString(lipgloss.NewStyle().Foreground(red).Bold(true).Render(
+			fmt.Sprintf("Delete %s check-in at %s?  %s", label, clock, confirmHelp()),
+		))
+	} else {
+		if m.statusMsg != "" {
+			content.WriteString(statusStyle(m.statusInfo).Render(m.statusMsg))
+			content.WriteString("\n")
+		}
+		content.WriteString(m.shortHelp())
+	}
+
+	b.WriteString(s.ContentBox.Render(content.String()))
+	return s.Base.Render(b.String())
+}
+
+func viewDayLogEntry(m Model) string {
+	s := m.styles
+	var b strings.Builder
+	b.WriteString(m.renderTitle())
+	b.WriteString("\n")
+	title := "Add Check-in"
+	if m.dayLogFields != nil && m.dayLogFields.entry != nil {
+		title = "Edit Check-in"
+	}
+	b.WriteString(m.appBoundaryView(title))
+	b.WriteString("\n\n")
+	var content strings.Builder
+	content.WriteString(m.form.View())
+	content.WriteString("\n\n")
+	content.WriteString(s.Help.Render(helpLine(helpItem("next / save", submitKey()), helpItem("cancel", keys.Back))))
+	b.WriteString(s.ContentBox.Render(content.String()))
+	return s.Base.Render(b.String())
+}
@@ -3,7 +3,6 @@ package view
 import (
 	"context"
 	"log"
-	"strconv"
 	"strings"
 
 	"github.com/bShaak/habitui/internal/models"
@@ -46,33 +45,43 @@ func updateEditHabit(m Model, msg tea.Msg) (tea.Model, tea.Cmd) {
 	if name == "" {
 		name = "Unnamed Habit"
 	}
-	goalInt, err := strconv.Atoi(m.formFields.GoalString)
-	if err != nil || goalInt < 1 {
-		log.Printf("Error converting goal to int: %v", err)
-		m.statusMsg = "Could not update habit: goal must be a number ≥ 1"
+	color, err := formColor(m.formFields)
+	if err != nil {
+		m.setError("Could not update habit: " + err.Error())
 		return returnToMain(m), nil
 	}
-	color := m.formFields.Color
-	if color == "" {
-		color = "red"
+	icon, err := formIcon(m.formFields)
+	if err != nil {
+		m.setError("Could not update habit: " + err.Error())
+		return returnToMain(m), nil
 	}
 	habit.Name = name
 	habit.Description = m.formFields.Description
-	habit.Goal = goalInt
-	habit.Frequency = formFrequency(m.formFields)
 	habit.Color = color
-	habit.Icon = m.formFields.Icon
+	habit.Icon = icon
+	habit.Tags = models.ParseTags(m.formFields.Tags)
+	if err := applyFormGoal(m.formFields, &habit); err != nil {
+		log.Printf("Error reading habit goal: %v", err)
+		m.setError("Could not update habit: " + err.Error())
+		return returnToMain(m), nil
+	}
+	if err := applyFormSchedule(m.formFields, &habit); err != nil {
+		log.Printf("Error reading habit schedule: %v", err)
+		m.setError("Could not update habit: " + err.Error())
+		return returnToMain(m), nil
+	}
 	if err := m.store.UpdateHabit(context.Background(), &habit); err != nil {
 		log.Printf("Error updating habit: %s", err)
-		m.statusMsg = "Could not update habit"
+		m.setError("Could not update habit")
 		return returnToMain(m), nil
 	}
-	for i, h := range m.habits {
+	for i, h := range m.allHabits {
 		if h.ID == habit.ID {
-			m.habits[i] = habit
+			m.allHabits[i] = habit
 			break
 		}
 	}
+	m = arrangeHabits(m)
 	m.statusMsg = ""
 	return returnToMain(m), nil
 }
@@ -88,7 +97,7 @@ func viewEditHabit(m Model) string {
 	var content strings.Builder
 	content.WriteString(m.form.View())
 	content.WriteString("\n\n")
-	help := s.Help.Render("tab: next  |  shift+tab: back  |  esc: cancel")
+	help := s.Help.Render(helpLine("tab: next", "shift+tab: back", helpItem("cancel", keys.Back)))
 	content.WriteString(help)
 	b.WriteString(s.ContentBox.Render(content.String()))
 	return s.Base.Render(b.String())
@@ -0,0 +1,330 @@
+package view
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bShaak/habitui/internal/models"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	detailHeatmapWeeks  = 12
+	detailRecentEntries = 8
+)
+
+// habitDetail is the detail screen's habit, its full check-in history and
+// the numbers derived from it, computed once per load rather than per render.
+type habitDetail struct {
+	habit         models.Habit
+	from          screen              // where esc returns
+	completions   []models.Completion // newest first
+	current, best int                 // streaks
+	periods       []statsPeriod       // 7 days through all time
+	stats         []habitStats        // parallel to periods
+}
+
+// openHabitDetail shows the details of the habit selected on from: the
+// archive's cursor on the archive screen, the habit list's otherwise.
+func openHabitDetail(m Model, from screen) Model {
+	m.detail = &habitDetail{from: from}
+	habits, i := m.detailHabits()
+	if len(habits) == 0 {
+		m.detail = nil
+		return m
+	}
+	m.statusMsg = ""
+	m.detail.habit = habits[i]
+	m = loadHabitDetail(m)
+	m.scrollOffset = 0
+	m.screen = screenHabitDetail
+	return m
+}
+
+// detailHabits is the list the detail screen steps through and the index of
+// the habit shown.
+func (m Model) detailHabits() ([]models.Habit, int) {
+	if m.detail != nil && m.detail.from == screenArchive {
+		return m.archivedHabits, m.archiveCursor
+	}
+	return m.habits, m.cursor
+}
+
+// stepHabitDetail shows the previous (delta -1) or next habit in the list.
+func stepHabitDetail(m Model, delta int) Model {
+	habits, i := m.detailHabits()
+	i += delta
+	if i < 0 || i >= len(habits) {
+		return m
+	}
+	if m.detail.from == screenArchive {
+		m.archiveCursor = i
+	} else {
+		m.cursor = i
+	}
+	m.detail.habit = habits[i]
+	return loadHabitDetail(m)
+}
+
+// loadHabitDetail fetches every check-in of the habit shown.
+func loadHabitDetail(m Model) Model {
+	completions, err := m.store.GetCompletionsByHabitID(context.Background(), m.detail.habit.ID)
+	if err != nil {
+		log.Printf("Error fetching habit history: %s", err)
+		m.setError("Could not load habit history")
+	}
+	sort.SliceStable(completions, func(i, j int) bool { return completedAfter(completions[i], completions[j]) })
+	habit := m.detail.habit
+	now := today()
+	history := historyFromCompletions(completions)
+	m.detail.completions = completions
+	m.detail.current, m.detail.best = streaksFromDays(habit, history.amounts(habit), m.pauses, now)
+	m.detail.periods, m.detail.stats = nil, nil
+	for tab := statsTab7Days; tab <= statsTabAllTime; tab++ {
+		period := statsPeriodFor(tab, 0, statsPeriod{}, []models.Habit{habit}, now)
+		m.detail.periods = append(m.detail.periods, period)
+		m.detail.stats = append(m.detail.stats, calculateStatsForHabit(habit, history, m.pauses, period))
+	}
+	return m
+}
+
+// closeHabitDetail returns to the screen the details were opened from.
+func closeHabitDetail(m Model) Model {
+	from := screenMain
+	if m.detail != nil {
+		from = m.detail.from
+	}
+	m.detail = nil
+	m.scrollOffset = 0
+	m.screen = from
+	return m
+}
+
+// selectHabit puts the cursor on the habit with id, if it is listed.
+func selectHabit(m Model, id int64) Model {
+	for i, h := range m.habits {
+		if h.ID == id {
+			m.cursor = i
+			break
+		}
+	}
+	return m
+}
+
+// openCalendarOnToday shows the current week with today and habitID selected.
+func openCalendarOnToday(m Model, habitID int64) Model {
+	m.weekStart = startOfWeek(today())
+	m = openCalendar(m)
+	m = selectHabit(m, habitID)
+	for col := 0; col < 7; col++ {
+		if m.weekStart.AddDate(0, 0, col).Equal(today()) {
+			m.calendarCol = col
+		}
+	}
+	return m
+}
+
+func updateHabitDetail(m Model, msg tea.Msg) (tea.Model, tea.Cmd) {
+	if m.detail == nil {
+		return closeHabitDetail(m), nil
+	}
+	archived := m.detail.habit.ArchivedAt != ""
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, keys.Quit):
+			return m, tea.Quit
+		case key.Matches(msg, keys.Up):
+			return stepHabitDetail(m, -1), nil
+		case key.Matches(msg, keys.Down):
+			return stepHabitDetail(m, 1), nil
+		case key.Matches(msg, keys.Edit) && !archived:
+			m.detail = nil
+			return openEditHabit(m)
+		case key.Matches(msg, keys.Archive) && !archived:
+			m.detail = nil
+			m = archiveSelectedHabit(m)
+			m.scrollOffset = 0
+			m.screen = screenMain
+			return m, nil
+		case key.Matches(msg, keys.Calendar) && !archived:
+			id := m.detail.habit.ID
+			m.detail = nil
+			return openCalendarOnToday(m, id), nil
+		}
+	}
+	return m, nil
+}
+
+// formatHabitTarget describes what a day needs, e.g. "2× a day" or "30 pages a day".
+func formatHabitTarget(habit models.Habit) string {
+	if habit.IsQuantity() {
+		return formatQuantity(habit.DailyTarget(), habit.Unit) + " a day"
+	}
+	if goal := effectiveGoal(habit.Goal); goal > 1 {
+		return fmt.Sprintf("%d× a day", goal)
+	}
+	return "once a day"
+}
+
+// formatHabitStart shows when a habit started and how long ago.
+func formatHabitStart(habit models.Habit) string {
+	start, ok := habitStartDay(habit)
+	if !ok {
+		return "—"
+	}
+	days := int(today().Sub(start).Hours()/24 + 0.5)
+	switch {
+	case days <= 0:
+		return start.Format("Jan 2, 2006") + " (today)"
+	case days == 1:
+		return start.Format("Jan 2, 2006") + " (yesterday)"
+	default:
+		return fmt.Sprintf("%s (%d days ago)", start.Format("Jan 2, 2006"), days)
+	}
+}
+
+// formatRecentCheckIn describes one check-in by its habit day, time and amount.
+func formatRecentCheckIn(habit models.Habit, c models.Completion) string {
+	day := c.Day()
+	if d, err := time.Parse(models.DayLayout, day); err == nil {
+		day = d.Format("Mon Jan 2, 2006")
+	}
+	clock := "--:--"
+	if t, ok := completionTime(c); ok {
+		clock = t.Format("15:04")
+	}
+	line := day + "  " + clock
+	if habit.IsQuantity() {
+		line += "  " + formatQuantity(c.Value, habit.Unit)
+	}
+	return line
+}
+
+func viewHabitDetail(m Model) string {
+	s := m.styles
+	var b strings.Builder
+	b.WriteString(m.renderTitle())
+	b.WriteString("\n")
+	b.WriteString(m.appBoundaryView("Habit Details"))
+	b.WriteString("\n\n")
+
+	if m.detail == nil {
+		b.WriteString(s.ContentBox.Render(m.shortHelp()))
+		return s.Base.Render(b.String())
+	}
+	habit := m.detail.habit
+	completions := m.detail.completions
+	accent := getHabitColor(habit.Color)
+	labelStyle := lipgloss.NewStyle().Foreground(subtext).Width(12)
+	valueStyle := lipgloss.NewStyle().Foreground(text)
+	headerStyle := lipgloss.NewStyle().Foreground(primary).Bold(true)
+
+	var content strings.Builder
+	content.WriteString(lipgloss.NewStyle().Foreground(accent).Bold(true).Render(habit.Label()))
+	content.WriteString("\n")
+	if habit.Description != "" {
+		content.WriteString(s.Help.Render(habit.Description))
+		content.WriteString("\n")
+	}
+	content.WriteString("\n")
+
+	now := today()
+	allTime := m.detail.stats[len(m.detail.stats)-1]
+
+	rows := [][2]string{
+		{"Schedule", habit.ScheduleLabel() + ", " + formatHabitTarget(habit)},
+		{"Started", formatHabitStart(habit)},
+	}
+	if habit.ArchivedAt != "" {
+		rows = append(rows, [2]string{"Archived", formatArchiveDate(habit.ArchivedAt)})
+	}
+	if len(habit.Tags) > 0 {
+		rows = append(rows, [2]string{"Tags", "#" + strings.Join(habit.Tags, " #")})
+	}
+	rows = append(rows,
+		[2]string{"Streak", fmt.Sprintf("%d days (best %d)", m.detail.current, m.detail.best)},
+		[2]string{"All time", fmt.Sprintf("%d check-ins, %d/%d days met (%s)",
+			allTime.TotalCompletions, allTime.GoalDaysMet, allTime.ScheduledDays, formatRate(allTime.CompletionRate))},
+	)
+	if habit.IsQuantity() {
+		rows = append(rows, [2]string{"Total", formatQuantity(allTime.TotalAmount, habit.Unit)})
+	}
+	for _, row := range rows {
+		content.WriteString(labelStyle.Render(row[0]))
+		content.WriteString(valueStyle.Render(row[1]))
+		content.WriteString("\n")
+	}
+	content.WriteString("\n")
+
+	content.WriteString(headerStyle.Render(fmt.Sprintf("Last %d weeks", detailHeatmapWeeks)))
+	content.WriteString("\n")
+	start := startOfWeek(now).AddDate(0, 0, -7*(detailHeatmapWeeks-1))
+	content.WriteString(renderHeatmapGrid([]models.Habit{habit}, completions, m.pauses, start, now, detailHeatmapWeeks, accent))
+	content.WriteString("\n")
+
+	content.WriteString(renderDetailPeriods(habit, m.detail.periods, m.detail.stats))
+	content.WriteString("\n")
+
+	content.WriteString(headerStyle.Render("Recent check-ins"))
+	content.WriteString("\n")
+	if len(completions) == 0 {
+		content.WriteString(s.Help.Render("No check-ins yet."))
+		content.WriteString("\n")
+	}
+	for i, c := range completions {
+		if i == detailRecentEntries {
+			content.WriteString(s.Help.Render(fmt.Sprintf("… and %d earlier", len(completions)-i)))
+			content.WriteString("\n")
+			break
+		}
+		content.WriteString(valueStyle.Render(formatRecentCheckIn(habit, c)))
+		content.WriteString("\n")
+	}
+	content.WriteString("\n")
+
+	if m.statusMsg != "" {
+		content.WriteString(statusStyle(m.statusInfo).Render(m.statusMsg))
+		content.WriteString("\n")
+	}
+	content.WriteString(m.shortHelp())
+
+	b.WriteString(s.ContentBox.Render(content.String()))
+	return s.Base.Render(b.String())
+}
+
+// renderDetailPeriods is the stats screen's numbers for one habit, one row
+// per fixed period.
+func renderDetailPeriods(habit models.Habit, periods []statsPeriod, stats []habitStats) string {
+	nameStyle := lipgloss.NewStyle().Foreground(subtext).Width(16)
+	cellStyle := lipgloss.NewStyle().Foreground(text).Width(12)
+	headStyle := lipgloss.NewStyle().Foreground(muted).Width(12)
+
+	var out strings.Builder
+	out.WriteString(nameStyle.Foreground(primary).Bold(true).Render("Period"))
+	out.WriteString(headStyle.Render("Completed"))
+	out.WriteString(headStyle.Render("Rate"))
+	if habit.IsQuantity() {
+		out.WriteString(headStyle.Render("Total"))
+		out.WriteString(headStyle.Render("Avg / Day"))
+	}
+	out.WriteString("\n")
+	for i, period := range periods {
+		st := stats[i]
+		out.WriteString(nameStyle.Render(period.Name))
+		out.WriteString(cellStyle.Render(fmt.Sprintf("%d/%d", st.GoalDaysMet, st.ScheduledDays)))
+		out.WriteString(cellStyle.Render(formatRate(st.CompletionRate)))
+		if habit.IsQuantity() {
+			out.WriteString(cellStyle.Render(formatQuantity(st.TotalAmount, habit.Unit)))
+			out.WriteString(cellStyle.Render(formatQuantity(st.AverageAmount, habit.Unit)))
+		}
+		out.WriteString("\n")
+	}
+	return out.String()
+}
@@ -2,46 +2,106 @@ package view
 
 import (
 	"errors"
+	"fmt"
 	"strconv"
+	"strings"
 
+	"github.com/bShaak/habitui/internal/icons"
 	"github.com/bShaak/habitui/internal/models"
 	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/huh"
 	"github.com/charmbracelet/lipgloss"
 )
 
-const formSelectHeight =6
+const formSelectHeight = 6
 const daySelectHeight = 8
 
+// customColor is the Color select value that reveals the custom color input.
+const customColor = "custom"
+
 type habitFormFields struct {
-	Name        string
-	Frequency   []string
-	GoalString  string
-	Description string
-	Color       string
-	Icon        string
-	Confirm     bool
+	Name         string
+	Kind         string
+	Frequency    []string
+	Repeat       string
+	IntervalStr  string
+	PerWeekStr   string
+	MonthDaysStr string
+	GoalString   string
+	Unit         string
+	TargetString string
+	Description  string
+	Tags         string
+	Color        string
+	CustomColor  string // used when Color is customColor
+	Icon         string
+	CustomIcon   string // used when Icon is customIcon
+	Confirm      bool
 }
 
 func newHabitFormFields() *habitFormFields {
 	return &habitFormFields{
-		Color: "red",
+		Kind:       models.HabitKindCount,
+		Repeat:     models.RecurrenceWeekdays,
+		GoalString: "1",
+		Color:      "red",
 	}
 }
 
 func habitFormFieldsFromHabit(habit models.Habit) *habitFormFields {
-	color := habit.Color
+	color, custom := habit.Color, ""
 	if color == "" {
 		color = "red"
 	}
+	if !models.IsThemeColor(color) {
+		if fixed, err := models.NormalizeColor(color); err == nil {
+			color, custom = customColor, fixed
+		} else {
+			color = "red"
+		}
+	}
+	icon, customGlyph := habit.Icon, ""
+	if icon != "" && !icons.Contains(icon, nerdFontIcons()) {
+		icon, customGlyph = customIcon, habit.Icon
+	}
+	kind := habit.Kind
+	if kind != models.HabitKindQuantity {
+		kind = models.HabitKindCount
+	}
+	target := ""
+	if habit.IsQuantity() {
+		target = models.FormatAmount(habit.DailyTarget())
+	}
+	repeat := habit.Recurrence.Kind
+	if repeat == "" {
+		repeat = models.RecurrenceWeekdays
+	}
+	intervalStr, perWeekStr := "", ""
+	if habit.Recurrence.Interval > 0 {
+		intervalStr = strconv.Itoa(habit.Recurrence.Interval)
+	}
+	if habit.Recurrence.TimesPerWeek > 0 {
+		perWeekStr = strconv.Itoa(habit.Recurrence.TimesPerWeek)
+	}
 	return &habitFormFields{
-		Name:        habit.Name,
-		GoalString:  strconv.Itoa(effectiveGoal(habit.Goal)),
-		Description: habit.Description,
-		Frequency:   frequencyDaysForForm(habit.Frequency),
-		Color:       color,
-		Icon:        habit.Icon,
-		Confirm:     false,
+		Name:         habit.Name,
+		Repeat:       repeat,
+		IntervalStr:  intervalStr,
+		PerWeekStr:   perWeekStr,
+		MonthDaysStr: models.FormatMonthDays(habit.Recurrence.MonthDays),
+		Kind:         kind,
+		GoalString:   strconv.Itoa(effectiveGoal(habit.Goal)),
+		Unit:         habit.Unit,
+		TargetString: target,
+		Description:  habit.Description,
+		Tags:         models.FormatTags(habit.Tags),
+		Frequency:    frequencyDaysForForm(habit.Frequency),
+		Color:        color,
+		CustomColor:  custom,
+		Icon:         icon,
+		CustomIcon:   customGlyph,
+		Confirm:      false,
 	}
 }
 
@@ -58,65 +118,150 @@ func buildHabitForm(fields *habitFormFields, confirmTitle string) *huh.Form {
 					}
 					return nil
 				}),
+			huh.NewSelect[string]().
+				Title("Track").
+				Key("kind").
+				Options(
+					huh.NewOption("Check-ins per day", models.HabitKindCount),
+					huh.NewOption("Amount per day (pages, km, L…)", models.HabitKindQuantity),
+				).
+				Value(&fields.Kind),
+			huh.NewText().
+				Title("Description").
+				Key("description").
+				CharLimit(400).
+				Lines(2).
+				Value(&fields.Description),
+			huh.NewInput().
+				Title("Tags").
+				Description("Comma-separated, e.g. health, work").
+				Key("tags").
+				Value(&fields.Tags),
+		),
+		huh.NewGroup(
 			huh.NewInput().
 				Title("Times per day").
 				Key("goal").
 				Value(&fields.GoalString).
 				Validate(func(str string) error {
-					goalInt, err := strconv.Atoi(str)
+					_, err := parseGoal(str)
+					return err
+				}),
+		).WithHideFunc(fields.isQuantity),
+		huh.NewGroup(
+			huh.NewInput().
+				Title("Unit").
+				Description("e.g. pages, km, L").
+				Key("unit").
+				CharLimit(12).
+				Value(&fields.Unit),
+			huh.NewInput().
+				Title("Daily target").
+				Key("target").
+				Value(&fields.TargetString).
+				Validate(func(str string) error {
+					_, err := parseTarget(str)
+					return err
+				}),
+		).WithHideFunc(func() bool { return !fields.isQuantity() }),
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Title("Repeat").
+				Key("repeat").
+				Options(
+					huh.NewOption("On specific weekdays", models.RecurrenceWeekdays),
+					huh.NewOption("Every N days", models.RecurrenceInterval),
+					huh.NewOption("N times per week, any days", models.RecurrenceWeekly),
+					huh.NewOption("On days of the month", models.RecurrenceMonthly),
+				).
+				Value(&fields.Repeat),
+		),
+		huh.NewGroup(
+			huh.NewInput().
+				Title("Every how many days?").
+				Key("interval").
+				Value(&fields.IntervalStr).
+				Validate(func(str string) error {
+					_, err := parseBoundedInt(str, 1, 365, "interval")
+					return err
+				}),
+		).WithHideFunc(fields.repeatIsNot(models.RecurrenceInterval)),
+		huh.NewGroup(
+			huh.NewInput().
+				Title("Times per week").
+				Key("per_week").
+				Value(&fields.PerWeekStr).
+				Validate(func(str string) error {
+					_, err := parseBoundedInt(str, 1, 7, "times per week")
+					return err
+				}),
+		).WithHideFunc(fields.repeatIsNot(models.RecurrenceWeekly)),
+		huh.NewGroup(
+			huh.NewInput().
+				Title("Days of the month").
+				Description("Comma-separated, e.g. 1, 15. Short months use their last day.").
+				Key("month_days").
+				Value(&fields.MonthDaysStr).
+				Validate(func(str string) error {
+					days, err := models.ParseMonthDays(str)
 					if err != nil {
-						return errors.New("goal must be a number")
-					}
-					if goalInt < 1 {
-						return errors.New("goal must be at least 1")
+						return err
 					}
-					return nil
+					_, err = models.Recurrence{Kind: models.RecurrenceMonthly, MonthDays: days}.Normalize()
+					return err
 				}),
-			huh.NewText().
-				Title("Description").
-				Key("description").
-				CharLimit(400).
-				Lines(2).
-				Value(&fields.Description),
-		),
+		).WithHideFunc(fields.repeatIsNot(models.RecurrenceMonthly)),
 		huh.NewGroup(
 			huh.NewMultiSelect[string]().
 				Title("Schedule").
 				Key("frequency").
-				Options(
-					huh.NewOption("Monday", "monday"),
-					huh.NewOption("Tuesday", "tuesday"),
-					huh.NewOption("Wednesday", "wednesday"),
-					huh.NewOption("Thursday", "thursday"),
-					huh.NewOption("Friday", "friday"),
-					huh.NewOption("Saturday", "saturday"),
-					huh.NewOption("Sunday", "sunday"),
-				).
+				Options(weekdayOptions()...).
 				Height(daySelectHeight).
 				Value(&fields.Frequency),
-		),
+		).WithHideFunc(fields.repeatIsNot(models.RecurrenceWeekdays)),
 		huh.NewGroup(
 			huh.NewSelect[string]().
 				Title("Color").
 				Key("color").
-				Options(
-					huh.NewOption("Red", "red"),
-					huh.NewOption("Blue", "blue"),
-					huh.NewOption("Green", "green"),
-					huh.NewOption("Yellow", "yellow"),
-					huh.NewOption("Orange", "orange"),
-					huh.NewOption("Purple", "purple"),
-					huh.NewOption("Pink", "pink"),
-				).
+				Description("Theme colors follow the theme; custom ones stay fixed").
+				Options(colorOptions()...).
 				Height(formSelectHeight).
 				Value(&fields.Color),
 			huh.NewSelect[string]().
 				Title("Icon").
 				Key("icon").
+				Description("Press / to search by name").
 				Options(habitIconOptions()...).
 				Height(formSelectHeight).
 				Value(&fields.Icon),
 		),
+		huh.NewGroup(
+			huh.NewInput().
+				Title("Custom color").
+				Key("custom_color").
+				Placeholder("#1e90ff or 0-255").
+				DescriptionFunc(func() string { return colorPreview(fields.CustomColor) }, &fields.CustomColor).
+				Value(&fields.CustomColor).
+				Validate(func(str string) error {
+					_, err := models.NormalizeColor(str)
+					return err
+				}),
+		).WithHideFunc(func() bool { return fields.Color != customColor }),
+		huh.NewGroup(
+			huh.NewInput().
+				Title("Custom icon").
+				Key("custom_icon").
+				Placeholder("🦄").
+				DescriptionFunc(func() string { return iconPreview(fields.CustomIcon) }, &fields.CustomIcon).
+				Value(&fields.CustomIcon).
+				Validate(func(str string) error {
+					if strings.TrimSpace(str) == "" {
+						return errors.New("enter an icon or pick None")
+					}
+					_, err := models.NormalizeIcon(str)
+					return err
+				}),
+		).WithHideFunc(func() bool { return fields.Icon != customIcon }),
 		huh.NewGroup(
 			huh.NewConfirm().
 				Title(confirmTitle).
@@ -125,7 +270,7 @@ func buildHabitForm(fields *habitFormFields, confirmTitle string) *huh.Form {
 				Negative("No").
 				Value(&fields.Confirm),
 		),
-	).WithWidth(60).WithTheme(formTheme())
+	).WithWidth(60).WithTheme(formTheme()).WithKeyMap(formKeyMap())
 }
 
 // formTheme builds a huh theme from the active Habitui palette so create/edit
@@ -194,9 +339,167 @@ func applyFormSize(form *huh.Form, width, height int) {
 	_ = height
 }
 
+// weekdayOptions lists the days in calendar order for the configured week start.
+func weekdayOptions() []huh.Option[string] {
+	opts := make([]huh.Option[string], 0, 7)
+	for _, d := range weekdayOrder() {
+		opts = append(opts, huh.NewOption(d.String(), strings.ToLower(d.String())))
+	}
+	return opts
+}
+
+// colorOptions lists the theme colors with a swatch in the current palette,
+// then the custom entry.
+func colorOptions() []huh.Option[string] {
+	opts := make([]huh.Option[string], 0, len(models.HabitColors)+1)
+	for _, c := range models.HabitColors {
+		label := strings.ToUpper(c[:1]) + c[1:]
+		opts = append(opts, huh.NewOption(lipgloss.NewStyle().Foreground(getHabitColor(c)).Render("●")+" "+label, c))
+	}
+	return append(opts, huh.NewOption("Custom (hex or 0-255)…", customColor))
+}
+
+// colorPreview shows the custom color as typed, or why it isn't usable yet.
+func colorPreview(value string) string {
+	if strings.TrimSpace(value) == "" {
+		return "Enter a hex value or a 256-color index"
+	}
+	c, err := models.NormalizeColor(value)
+	if err != nil {
+		return "Not a color yet"
+	}
+	return lipgloss.NewStyle().Foreground(lipgloss.Color(c)).Render("████ Preview " + c)
+}
+
+// formColor is the habit color chosen in the form.
+func formColor(fields *habitFormFields) (string, error) {
+	if fields.Color == customColor {
+		return models.NormalizeColor(fields.CustomColor)
+	}
+	if fields.Color == "" {
+		return "red", nil
+	}
+	return fields.Color, nil
+}
+
+func (f *habitFormFields) isQuantity() bool {
+	return f.Kind == models.HabitKindQuantity
+}
+
+func parseGoal(str string) (int, error) {
+	goalInt, err := strconv.Atoi(strings.TrimSpace(str))
+	if err != nil {
+		return 0, errors.New("goal must be a number")
+	}
+	if goalInt < 1 {
+		return 0, errors.New("goal must be at least 1")
+	}
+	return goalInt, nil
+}
+
+func parseTarget(str string) (float64, error) {
+	target, err := parseAmount(str)
+	if err != nil {
+		return 0, errors.New("target must be a number")
+	}
+	if target <= 0 {
+		return 0, errors.New("target must be greater than 0")
+	}
+	return target, nil
+}
+
+// applyFormGoal copies the tracking kind and its goal or target from the form onto habit.
+func applyFormGoal(fields *habitFormFields, habit *models.Habit) error {
+	if fields.isQuantity() {
+		target, err := parseTarget(fields.TargetString)
+		if err != nil {
+			return err
+		}
+		habit.Kind = models.HabitKindQuantity
+		habit.Unit = strings.TrimSpace(fields.Unit)
+		habit.Target = target
+		habit.Goal = 1
+		return nil
+	}
+	goal, err := parseGoal(fields.GoalString)
+	if err != nil {
+		return err
+	}
+	habit.Kind = models.HabitKindCount
+	habit.Unit = ""
+	habit.Target = 0
+	habit.Goal = goal
+	return nil
+}
+
+func (f *habitFormFields) repeatIsNot(kind string) func() bool {
+	return func() bool { return f.Repeat != kind }
+}
+
+func parseBoundedInt(str string, lo, hi int, what string) (int, error) {
+	n, err := strconv.Atoi(strings.TrimSpace(str))
+	if err != nil {
+		return 0, fmt.Errorf("%s must be a number", what)
+	}
+	if n < lo || n > hi {
+		return 0, fmt.Errorf("%s must be between %d and %d", what, lo, hi)
+	}
+	return n, nil
+}
+
+// applyFormSchedule copies the weekday list or structured recurrence from the form onto habit.
+func applyFormSchedule(fields *habitFormFields, habit *models.Habit) error {
+	habit.Frequency = formFrequency(fields)
+	var r models.Recurrence
+	switch fields.Repeat {
+	case models.RecurrenceInterval:
+		n, err := parseBoundedInt(fields.IntervalStr, 1, 365, "interval")
+		if err != nil {
+			return err
+		}
+		r = models.Recurrence{Kind: models.RecurrenceInterval, Interval: n}
+	case models.RecurrenceWeekly:
+		n, err := parseBoundedInt(fields.PerWeekStr, 1, 7, "times per week")
+		if err != nil {
+			return err
+		}
+		r = models.Recurrence{Kind: models.RecurrenceWeekly, TimesPerWeek: n}
+	case models.RecurrenceMonthly:
+		days, err := models.ParseMonthDays(fields.MonthDaysStr)
+		if err != nil {
+			return err
+		}
+		r = models.Recurrence{Kind: models.RecurrenceMonthly, MonthDays: days}
+	}
+	normalized, err := r.Normalize()
+	if err != nil {
+		return err
+	}
+	habit.Recurrence = normalized
+	if !normalized.IsWeekdays() {
+		habit.Frequency = "daily"
+	}
+	return nil
+}
+
 func formFrequency(fields *habitFormFields) string {
 	if fields == nil {
 		return "daily"
 	}
 	return normalizeFrequency(fields.Frequency)
 }
+
+// formKeyMap moves huh's enter bindings onto the configured submit keys so the
+// form help lines stay true when Confirm is remapped. tab still advances.
+func formKeyMap() *huh.KeyMap {
+	km := huh.NewDefaultKeyMap()
+	submit := submitKey()
+	next := key.NewBinding(key.WithKeys(append(submit.Keys(), "tab")...), key.WithHelp(submit.Help().Key, "next"))
+	km.Input.Next, km.Input.Submit = next, submit
+	km.Text.Next, km.Text.Submit = next, submit
+	km.Select.Next, km.Select.Submit = next, submit
+	km.MultiSelect.Next, km.MultiSelect.Submit = next, submit
+	km.Note.Next, km.Note.Submit = next, submit
+	km.Confirm.Next, km.Confirm.Submit = next, submit
+	return km
+}
@@ -1,24 +1,51 @@
 package view
 
-import "github.com/charmbracelet/huh"
+import (
+	"strings"
 
+	"github.com/bShaak/habitui/internal/icons"
+	"github.com/bShaak/habitui/internal/models"
+	"github.com/charmbracelet/huh"
+)
+
+// customIcon is the Icon select value that reveals the free-form icon input.
+const customIcon = "custom"
+
+// nerdFontIcons reports whether the config turns on the Nerd Font glyph set.
+func nerdFontIcons() bool {
+	return themeConfig != nil && themeConfig.NerdFont
+}
+
+// habitIconOptions lists the icon catalog; "/" filters it by name or keyword.
 func habitIconOptions() []huh.Option[string] {
-	return []huh.Option[string]{
+	catalog := icons.Catalog(nerdFontIcons())
+	opts := make([]huh.Option[string], 0, len(catalog)+2)
+	opts = append(opts,
 		huh.NewOption("None", ""),
-		huh.NewOption("🏃 Running", "🏃"),
-		huh.NewOption("💪 Strength", "💪"),
-		huh.NewOption("🧘 Meditation", "🧘"),
-		huh.NewOption("📚 Reading", "📚"),
-		huh.NewOption("✍️ Writing", "✍️"),
-		huh.NewOption("💧 Water", "💧"),
-		huh.NewOption("🥗 Nutrition", "🥗"),
-		huh.NewOption("😴 Sleep", "😴"),
-		huh.NewOption("🎯 Focus", "🎯"),
-		huh.NewOption("💻 Coding", "💻"),
-		huh.NewOption("🎵 Music", "🎵"),
-		huh.NewOption("🧹 Chores", "🧹"),
-		huh.NewOption("🚶 Walk", "🚶"),
-		huh.NewOption("🧠 Study", "🧠"),
-		huh.NewOption("❤️ Health", "❤️"),
+		huh.NewOption("Custom (any emoji or glyph)…", customIcon),
+	)
+	for _, icon := range catalog {
+		opts = append(opts, huh.NewOption(icon.Label(), icon.Glyph))
+	}
+	return opts
+}
+
+// iconPreview shows the custom icon in a habit label, or why it can't be used.
+func iconPreview(value string) string {
+	if strings.TrimSpace(value) == "" {
+		return "Paste or type an emoji"
+	}
+	icon, err := models.NormalizeIcon(value)
+	if err != nil {
+		return "Use one emoji or glyph, at most 2 columns wide"
+	}
+	return "Preview: " + icon + " Habit"
+}
+
+// formIcon is the habit icon chosen in the form.
+func formIcon(fields *habitFormFields) (string, error) {
+	if fields.Icon == customIcon {
+		return models.NormalizeIcon(fields.CustomIcon)
 	}
+	return fields.Icon, nil
 }
@@ -0,0 +1,215 @@
+package view
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+
+	"github.com/bShaak/habitui/internal/models"
+	"github.com/bShaak/habitui/internal/theme"
+	"github.com/lucasb-eyer/go-colorful"
+)
+
+// Sort modes for the habit list. Every mode falls back to the manual order for ties.
+const (
+	sortManual     = "manual"
+	sortIncomplete = "incomplete"
+	sortStreak     = "streak"
+	sortColor      = "color"
+)
+
+var sortModes = []string{sortManual, sortIncomplete, sortStreak, sortColor}
+
+var sortModeLabels = map[string]string{
+	sortManual:     "manual",
+	sortIncomplete: "incomplete first",
+	sortStreak:     "by streak",
+	sortColor:      "by color",
+}
+
+func currentSortMode() string {
+	if themeConfig != nil {
+		for _, mode := range sortModes {
+			if themeConfig.SortMode == mode {
+				return mode
+			}
+		}
+	}
+	return sortManual
+}
+
+// manualOrder returns habits sorted by their stored position.
+func manualOrder(habits []models.Habit) []models.Habit {
+	out := append([]models.Habit(nil), habits...)
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].SortOrder != out[j].SortOrder {
+			return out[i].SortOrder < out[j].SortOrder
+		}
+		return out[i].ID < out[j].ID
+	})
+	return out
+}
+
+// arrangeHabits derives m.habits from m.allHabits: it applies the tag filter and
+// search query and sorts for the current sort mode, keeping the cursor on the
+// same habit.
+func arrangeHabits(m Model) Model {
+	var selected int64
+	if m.cursor >= 0 && m.cursor < len(m.habits) {
+		selected = m.habits[m.cursor].ID
+	}
+
+	var habits []models.Habit
+	for _, h := range manualOrder(m.allHabits) {
+		if m.tagFilter == "" || h.HasTag(m.tagFilter) {
+			habits = append(habits, h)
+		}
+	}
+	if len(habits) == 0 && m.tagFilter != "" {
+		// The last habit with this tag is gone; fall back to everything.
+		m.tagFilter = ""
+		return arrangeHabits(m)
+	}
+	if query := m.searchQuery(); query != "" {
+		matched := habits[:0]
+		for _, h := range habits {
+			if matchesSearch(h, query) {
+				matched = append(matched, h)
+			}
+		}
+		habits = matched
+	}
+
+	switch currentSortMode() {
+	case sortIncomplete:
+		done := make(map[int64]bool, len(habits))
+		for _, h := range habits {
+			done[h.ID] = isCompleted(m.completions, h)
+		}
+		sort.SliceStable(habits, func(i, j int) bool {
+			return !done[habits[i].ID] && done[habits[j].ID]
+		})
+	case sortStreak:
+		now := today()
+		streaks := make(map[int64]int, len(habits))
+		for _, h := range habits {
+			streaks[h.ID], _ = getHabitStreak(h, m.streakCompletions, m.pauses, now)
+		}
+		sort.SliceStable(habits, func(i, j int) bool {
+			return streaks[habits[i].ID] > streaks[habits[j].ID]
+		})
+	case sortColor:
+		sort.SliceStable(habits, func(i, j int) bool {
+			ri, hi := colorRank(habits[i].Color)
+			rj, hj := colorRank(habits[j].Color)
+			if ri != rj {
+				return ri < rj
+			}
+			return hi < hj
+		})
+	}
+
+	m.habits = habits
+	m.cursor = 0
+	for i, h := range habits {
+		if h.ID == selected {
+			m.cursor = i
+			break
+		}
+	}
+	return m
+}
+
+// colorRank orders theme colors first, then custom colors by hue so similar
+// shades end up next to each other.
+func colorRank(color string) (int, float64) {
+	for i, c := range models.HabitColors {
+		if c == color {
+			return i, 0
+		}
+	}
+	c, err := colorful.Hex(string(getHabitColor(color)))
+	if err != nil {
+		return len(models.HabitColors) + 1, 0
+	}
+	hue, _, _ := c.Hsv()
+	return len(models.HabitColors), hue
+}
+
+// reloadHabits fetches the active habits and arranges them for display.
+func reloadHabits(m Model) Model {
+	habits, err := m.store.ListHabits(context.Background())
+	if err != nil {
+		log.Printf("Error fetching habits: %s", err)
+		return m
+	}
+	m.allHabits = habits
+	return arrangeHabits(m)
+}
+
+func cycleSortMode(m Model) Model {
+	if themeConfig == nil {
+		themeConfig = &theme.Config{}
+	}
+	mode := sortModes[0]
+	for i, s := range sortModes {
+		if s == currentSortMode() {
+			mode = sortModes[(i+1)%len(sortModes)]
+			break
+		}
+	}
+	themeConfig.SortMode = mode
+	if err := theme.SaveConfig(themeConfig); err != nil {
+		log.Printf("Error saving sort mode: %s", err)
+	}
+	m = arrangeHabits(m)
+	m.setStatus("Sort: " + sortModeLabels[mode])
+	return m
+}
+
+// moveSelectedHabit swaps the selected habit with its visible neighbour (delta
+// -1 or +1) and persists the new manual order. With a tag filter active, the
+// two habits trade places in the full list.
+func moveSelectedHabit(m Model, delta int) Model {
+	if len(m.habits) == 0 {
+		return m
+	}
+	if currentSortMode() != sortManual {
+		m.setError(fmt.Sprintf("Switch to manual sort (%s) to reorder habits", helpKey(keys.Sort)))
+		return m
+	}
+	target := m.cursor + delta
+	if target < 0 || target >= len(m.habits) {
+		return m
+	}
+
+	habits := manualOrder(m.allHabits)
+	from, to := -1, -1
+	for i, h := range habits {
+		switch h.ID {
+		case m.habits[m.cursor].ID:
+			from = i
+		case m.habits[target].ID:
+			to = i
+		}
+	}
+	if from < 0 || to < 0 {
+		return m
+	}
+	habits[from], habits[to] = habits[to], habits[from]
+	ids := make([]int64, len(habits))
+	for i := range habits {
+		habits[i].SortOrder = i + 1
+		ids[i] = habits[i].ID
+	}
+	if err := m.store.ReorderHabits(context.Background(), ids); err != nil {
+		log.Printf("Error reordering habits: %s", err)
+		m.setError("Could not reorder habits")
+		return m
+	}
+	m.allHabits = habits
+	m = arrangeHabits(m)
+	m.statusMsg = ""
+	return m
+}
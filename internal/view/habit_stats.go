@@ -10,6 +10,8 @@ import (
 type habitStats struct {
 	Habit            models.Habit
 	TotalCompletions int
+	TotalAmount      float64 // quantity habits: sum of logged values in the period
+	AverageAmount    float64 // quantity habits: TotalAmount per scheduled day
 	GoalDaysMet      int
 	ScheduledDays    int
 	CompletionRate   float64
@@ -21,26 +23,114 @@ type statsPeriod struct {
 	Name      string
 	StartDate time.Time
 	EndDate   time.Time
+	AllTime   bool // each habit's stats start at its own StartDate
 }
 
-func getStatsPeriods() []statsPeriod {
-	now := time.Now()
-	todayStart := startOfDay(now)
-	todayEnd := endOfDay(now)
+// Stats tabs, in display order.
+const (
+	statsTab7Days = iota
+	statsTab30Days
+	statsTab365Days
+	statsTabMonth
+	statsTabYear
+	statsTabAllTime
+	statsTabCustom
+)
+
+var statsTabNames = []string{"7 Days", "30 Days", "365 Days", "Month", "Year", "All Time", "Custom"}
 
-	return []statsPeriod{
-		{Name: "Last 7 Days", StartDate: todayStart.AddDate(0, 0, -6), EndDate: todayEnd},
-		{Name: "Last 30 Days", StartDate: todayStart.AddDate(0, 0, -29), EndDate: todayEnd},
-		{Name: "Last Year", StartDate: todayStart.AddDate(0, 0, -364), EndDate: todayEnd},
+// statsPeriodFor returns the period shown on tab, stepped offset periods back
+// from the current one (negative offsets step forward). Ranges never extend
+// past today; custom ranges step by their own length.
+func statsPeriodFor(tab, offset int, custom statsPeriod, habits []models.Habit, now time.Time) statsPeriod {
+	today := startOfDay(now)
+	switch tab {
+	case statsTab7Days, statsTab30Days, statsTab365Days:
+		days := map[int]int{statsTab7Days: 7, statsTab30Days: 30, statsTab365Days: 365}[tab]
+		end := today.AddDate(0, 0, -offset*days)
+		p := statsPeriod{StartDate: end.AddDate(0, 0, -(days - 1)), EndDate: endOfDay(end)}
+		p.Name = fmt.Sprintf("Last %d Days", days)
+		if offset != 0 {
+			p.Name = formatPeriodRange(p.StartDate, p.EndDate)
+		}
+		return p
+	case statsTabMonth:
+		first := time.Date(today.Year(), today.Month()-time.Month(offset), 1, 0, 0, 0, 0, today.Location())
+		last := first.AddDate(0, 1, -1)
+		return statsPeriod{Name: first.Format("January 2006"), StartDate: first, EndDate: endOfDay(minTime(last, today))}
+	case statsTabYear:
+		first := time.Date(today.Year()-offset, 1, 1, 0, 0, 0, 0, today.Location())
+		last := first.AddDate(1, 0, -1)
+		return statsPeriod{Name: first.Format("2006"), StartDate: first, EndDate: endOfDay(minTime(last, today))}
+	case statsTabAllTime:
+		start := today
+		for _, h := range habits {
+			if s, ok := habitStartDay(h); ok && s.Before(start) {
+				start = s
+			}
+		}
+		return statsPeriod{Name: "All Time", StartDate: start, EndDate: endOfDay(today), AllTime: true}
+	default:
+		if custom.StartDate.IsZero() {
+			return statsPeriod{}
+		}
+		days := int(startOfDay(custom.EndDate).Sub(startOfDay(custom.StartDate)).Hours()/24+0.5) + 1
+		start := custom.StartDate.AddDate(0, 0, -offset*days)
+		end := custom.EndDate.AddDate(0, 0, -offset*days)
+		return statsPeriod{Name: formatPeriodRange(start, end), StartDate: start, EndDate: endOfDay(minTime(end, today))}
 	}
 }
 
-func countScheduledDaysInRange(habit models.Habit, startDate, endDate time.Time) int {
+func minTime(a, b time.Time) time.Time {
+	if b.Before(a) {
+		return b
+	}
+	return a
+}
+
+func formatPeriodRange(start, end time.Time) string {
+	if start.Year() == end.Year() {
+		return start.Format("Jan 2") + " – " + end.Format("Jan 2, 2006")
+	}
+	return start.Format("Jan 2, 2006") + " – " + end.Format("Jan 2, 2006")
+}
+
+// habitStartDay returns the day a habit started, if it has a valid StartDate.
+func habitStartDay(habit models.Habit) (time.Time, bool) {
+	parsed, err := time.Parse(time.RFC3339, habit.StartDate)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return models.HabitDay(parsed), true
+}
+
+// periodForHabit narrows an all-time period to start at the habit's StartDate.
+func periodForHabit(habit models.Habit, period statsPeriod) statsPeriod {
+	if !period.AllTime {
+		return period
+	}
+	if start, ok := habitStartDay(habit); ok {
+		period.StartDate = start
+	}
+	return period
+}
+
+// countScheduledDaysInRange counts due days in [startDate, endDate], leaving out
+// skipped and vacation days. A weekly quota contributes its quota per week,
+// capped by the week's open days in range.
+func countScheduledDaysInRange(habit models.Habit, pauses models.Pauses, startDate, endDate time.Time) int {
+	if habit.Recurrence.IsWeeklyQuota() {
+		count := 0
+		forEachWeekInRange(startDate, endDate, func(_ time.Time, daysInRange []time.Time) {
+			count += min(habit.Recurrence.TimesPerWeek, openDays(habit, pauses, daysInRange))
+		})
+		return count
+	}
 	count := 0
 	current := startOfDay(startDate)
 	end := startOfDay(endDate)
 	for !current.After(end) {
-		if isScheduledOnDay(habit.Frequency, getDayName(current)) {
+		if habit.ScheduledOn(current) && !pauses.IsPaused(habit.ID, current) {
 			count++
 		}
 		current = current.AddDate(0, 0, 1)
@@ -48,52 +138,126 @@ func countScheduledDaysInRange(habit models.Habit, startDate, endDate time.Time)
 	return count
 }
 
-func completionsByDay(completions []models.Completion, habitID int64) map[string]int {
-	byDay := make(map[string]int)
-	for _, c := range completions {
-		if c.HabitID != habitID {
-			continue
+// forEachWeekInRange calls fn for every calendar week overlapping [startDate, endDate]
+// with that week's start and the days of it that fall inside the range.
+func forEachWeekInRange(startDate, endDate time.Time, fn func(weekStart time.Time, daysInRange []time.Time)) {
+	start := startOfDay(startDate)
+	end := startOfDay(endDate)
+	for weekStart := startOfWeek(start); !weekStart.After(end); weekStart = weekStart.AddDate(0, 0, 7) {
+		var days []time.Time
+		for i := 0; i < 7; i++ {
+			d := weekStart.AddDate(0, 0, i)
+			if !d.Before(start) && !d.After(end) {
+				days = append(days, d)
+			}
+		}
+		fn(weekStart, days)
+	}
+}
+
+// weekDaysMet counts the days of the week starting at weekStart on which habit met its target.
+func weekDaysMet(habit models.Habit, byDay map[string]float64, weekStart time.Time) int {
+	met := 0
+	for i := 0; i < 7; i++ {
+		if habit.TargetMet(byDay[weekStart.AddDate(0, 0, i).Format(models.DayLayout)]) {
+			met++
+		}
+	}
+	return met
+}
+
+// openDays counts the days that are not skipped or on vacation for habit.
+func openDays(habit models.Habit, pauses models.Pauses, days []time.Time) int {
+	n := 0
+	for _, d := range days {
+		if !pauses.IsPaused(habit.ID, d) {
+			n++
 		}
-		completedAt, err := time.Parse(time.RFC3339, c.CompletedAt)
-		if err != nil {
+	}
+	return n
+}
+
+// weeklyQuota is the week's quota, lowered when skips and vacations leave fewer open days.
+func weeklyQuota(habit models.Habit, pauses models.Pauses, weekStart time.Time) int {
+	days := make([]time.Time, 7)
+	for i := range days {
+		days[i] = weekStart.AddDate(0, 0, i)
+	}
+	return min(habit.Recurrence.TimesPerWeek, openDays(habit, pauses, days))
+}
+
+// weeklyQuotaProgress returns how many days met the target in day's week, and the quota.
+func weeklyQuotaProgress(habit models.Habit, completions []models.Completion, pauses models.Pauses, day time.Time) (int, int) {
+	weekStart := startOfWeek(day)
+	return weekDaysMet(habit, amountsByDay(completions, habit), weekStart), weeklyQuota(habit, pauses, weekStart)
+}
+
+// isDueOn reports whether habit still needs doing on day. Skipped and vacation
+// days are never due; weekly-quota habits stop being due once the week's quota is met.
+func isDueOn(habit models.Habit, completions []models.Completion, pauses models.Pauses, day time.Time) bool {
+	if pauses.IsPaused(habit.ID, day) {
+		return false
+	}
+	if habit.Recurrence.IsWeeklyQuota() {
+		met, quota := weeklyQuotaProgress(habit, completions, pauses, day)
+		return met < quota
+	}
+	return habit.ScheduledOn(day)
+}
+
+// amountsByDay sums habit's progress per local calendar day (check-ins or logged values).
+func amountsByDay(completions []models.Completion, habit models.Habit) map[string]float64 {
+	byDay := make(map[string]float64)
+	for _, c := range completions {
+		if c.HabitID != habit.ID {
 			continue
 		}
-		// Normalize to local calendar day so keys match startOfDay(time.Now()).
-		localDay := startOfDay(completedAt.In(time.Local)).Format("2006-01-02")
-		byDay[localDay]++
+		byDay[c.Day()] += habit.CompletionAmount(c)
 	}
 	return byDay
 }
 
 func getCompletionsForHabitInRange(completions []models.Completion, habitID int64, startDate, endDate time.Time) int {
-	count := 0
-	start := startOfDay(startDate)
-	end := endOfDay(endDate)
+	return len(completionsForHabitInRange(completions, habitID, startDate, endDate))
+}
+
+func completionsForHabitInRange(completions []models.Completion, habitID int64, startDate, endDate time.Time) []models.Completion {
+	var out []models.Completion
+	start := models.DayKey(startDate)
+	end := models.DayKey(endDate)
 	for _, c := range completions {
 		if c.HabitID != habitID {
 			continue
 		}
-		completedAt, err := time.Parse(time.RFC3339, c.CompletedAt)
-		if err != nil {
-			continue
-		}
-		if (completedAt.Equal(start) || completedAt.After(start)) &&
-			(completedAt.Equal(end) || completedAt.Before(end)) {
-			count++
+		// Day keys sort chronologically, so plain string compares suffice.
+		if day := c.Day(); day != "" && start <= day && day <= end {
+			out = append(out, c)
 		}
 	}
-	return count
+	return out
 }
 
-func countGoalDaysMetInRange(habit models.Habit, completions []models.Completion, startDate, endDate time.Time) int {
-	byDay := completionsByDay(completions, habit.ID)
-	goal := effectiveGoal(habit.Goal)
+func countGoalDaysMetInRange(habit models.Habit, byDay map[string]float64, startDate, endDate time.Time) int {
+	if habit.Recurrence.IsWeeklyQuota() {
+		// Extra days beyond the quota don't inflate a week's score.
+		met := 0
+		forEachWeekInRange(startDate, endDate, func(_ time.Time, daysInRange []time.Time) {
+			weekMet := 0
+			for _, d := range daysInRange {
+				if habit.TargetMet(byDay[d.Format(models.DayLayout)]) {
+					weekMet++
+				}
+			}
+			met += min(habit.Recurrence.TimesPerWeek, weekMet)
+		})
+		return met
+	}
 	met := 0
 	current := startOfDay(startDate)
 	end := startOfDay(endDate)
 	for !current.After(end) {
-		dayKey := current.Format("2006-01-02")
-		if byDay[dayKey] >= goal {
+		dayKey := current.Format(models.DayLayout)
+		if habit.TargetMet(byDay[dayKey]) {
 			// Count any day the goal was met, including off-schedule check-ins.
 			met++
 		}
@@ -102,26 +266,106 @@ func countGoalDaysMetInRange(habit models.Habit, completions []models.Completion
 	return met
 }
 
-// getHabitStreak returns current and longest streaks.
-// A day counts when completions that day >= goal.
+// dayStatus reports whether a day must be met to keep a streak going and
+// whether it was met.
+type dayStatus func(d time.Time) (required, met bool)
+
+// habitDayStatus builds the streak rules for one habit.
+// A day counts when that day's progress reaches the habit's daily target.
 // Unscheduled days with no completions neither count nor break the streak.
 // Unscheduled days that were completed do count (so off-day check-ins aren't ignored).
-func getHabitStreak(habit models.Habit, completions []models.Completion, today time.Time) (int, int) {
-	byDay := completionsByDay(completions, habit.ID)
-	goal := effectiveGoal(habit.Goal)
+// Weekly-quota habits only break on a missed day inside a finished week whose quota failed.
+// Skipped and vacation days are never required.
+func habitDayStatus(habit models.Habit, byDay map[string]float64, pauses models.Pauses, today time.Time) dayStatus {
+	currentWeek := startOfWeek(today)
+	quotaFailed := make(map[time.Time]bool)
+	return func(d time.Time) (bool, bool) {
+		met := habit.TargetMet(byDay[startOfDay(d).In(time.Local).Format(models.DayLayout)])
+		if pauses.IsPaused(habit.ID, d) {
+			return false, met
+		}
+		if !habit.Recurrence.IsWeeklyQuota() {
+			return habit.ScheduledOn(d), met
+		}
+		weekStart := startOfWeek(d)
+		if !weekStart.Before(currentWeek) {
+			return false, met
+		}
+		failed, ok := quotaFailed[weekStart]
+		if !ok {
+			failed = weekDaysMet(habit, byDay, weekStart) < weeklyQuota(habit, pauses, weekStart)
+			quotaFailed[weekStart] = failed
+		}
+		return failed, met
+	}
+}
 
-	dayMet := func(d time.Time) bool {
-		return byDay[startOfDay(d).In(time.Local).Format("2006-01-02")] >= goal
+// habitStreakStart is where the longest-streak scan begins: the habit's start
+// date, or the lookback window if that is later.
+func habitStreakStart(habit models.Habit, today time.Time) time.Time {
+	start := startOfDay(today).AddDate(-streakLookbackYears, 0, 0)
+	if parsedStart, ok := habitStartDay(habit); ok && parsedStart.After(start) {
+		start = parsedStart
 	}
+	return start
+}
+
+// getHabitStreak returns current and longest streaks.
+func getHabitStreak(habit models.Habit, completions []models.Completion, pauses models.Pauses, today time.Time) (int, int) {
+	return streaksFromDays(habit, amountsByDay(completions, habit), pauses, today)
+}
 
+// streaksFromDays is getHabitStreak over per-day amounts.
+func streaksFromDays(habit models.Habit, byDay map[string]float64, pauses models.Pauses, today time.Time) (int, int) {
+	return streaksFromStatus(habitDayStatus(habit, byDay, pauses, today), habitStreakStart(habit, today), today)
+}
+
+// getGroupStreak returns current and longest streaks for a set of habits taken
+// together: a day counts when every habit required that day met its goal.
+func getGroupStreak(habits []models.Habit, history habitHistory, pauses models.Pauses, today time.Time) (int, int) {
+	if len(habits) == 0 {
+		return 0, 0
+	}
+	statuses := make([]dayStatus, len(habits))
+	starts := make([]time.Time, len(habits))
+	start := startOfDay(today)
+	for i, h := range habits {
+		statuses[i] = habitDayStatus(h, history.amounts(h), pauses, today)
+		starts[i] = habitStreakStart(h, today)
+		if starts[i].Before(start) {
+			start = starts[i]
+		}
+	}
+	group := func(d time.Time) (bool, bool) {
+		anyRequired, anyMet, allRequiredMet := false, false, true
+		for i, status := range statuses {
+			if d.Before(starts[i]) {
+				// Habits added later don't count against earlier days.
+				continue
+			}
+			required, met := status(d)
+			anyRequired = anyRequired || required
+			anyMet = anyMet || met
+			if required && !met {
+				allRequiredMet = false
+			}
+		}
+		return anyRequired, anyMet && allRequiredMet
+	}
+	return streaksFromStatus(group, start, today)
+}
+
+// streaksFromStatus walks days with status to find the current streak (ending
+// today, with a grace day so an unfinished today doesn't reset it) and the
+// longest streak since start.
+func streaksFromStatus(status dayStatus, start, today time.Time) (int, int) {
 	// Current streak: walk backward from today.
 	currentStreak := 0
 	checkDate := startOfDay(today)
 	graceForToday := true
 	maxLookbackDays := 365 * streakLookbackYears
 	for i := 0; i < maxLookbackDays; i++ {
-		scheduled := isScheduledOnDay(habit.Frequency, getDayName(checkDate))
-		met := dayMet(checkDate)
+		scheduled, met := status(checkDate)
 
 		if !scheduled && !met {
 			checkDate = checkDate.AddDate(0, 0, -1)
@@ -143,21 +387,11 @@ func getHabitStreak(habit models.Habit, completions []models.Completion, today t
 		break
 	}
 
-	// Longest streak: scan from habit start (or lookback window) through today.
-	start := startOfDay(today).AddDate(-streakLookbackYears, 0, 0)
-	if habit.StartDate != "" {
-		if parsed, err := time.Parse(time.RFC3339, habit.StartDate); err == nil {
-			parsedStart := startOfDay(parsed.In(time.Local))
-			if parsedStart.After(start) {
-				start = parsedStart
-			}
-		}
-	}
+	// Longest streak: scan from start through today.
 	longestStreak := 0
 	tempStreak := 0
 	for d := start; !d.After(startOfDay(today)); d = d.AddDate(0, 0, 1) {
-		scheduled := isScheduledOnDay(habit.Frequency, getDayName(d))
-		met := dayMet(d)
+		scheduled, met := status(d)
 		if !scheduled && !met {
 			continue
 		}
@@ -174,10 +408,17 @@ func getHabitStreak(habit models.Habit, completions []models.Completion, today t
 	return currentStreak, longestStreak
 }
 
-func calculateStatsForHabit(habit models.Habit, completions []models.Completion, period statsPeriod) habitStats {
-	scheduledDays := countScheduledDaysInRange(habit, period.StartDate, period.EndDate)
-	goalDaysMet := countGoalDaysMetInRange(habit, completions, period.StartDate, period.EndDate)
-	totalCompletions := getCompletionsForHabitInRange(completions, habit.ID, period.StartDate, period.EndDate)
+func calculateStatsForHabit(habit models.Habit, history habitHistory, pauses models.Pauses, period statsPeriod) habitStats {
+	period = periodForHabit(habit, period)
+	byDay := history.amounts(habit)
+	scheduledDays := countScheduledDaysInRange(habit, pauses, period.StartDate, period.EndDate)
+	goalDaysMet := countGoalDaysMetInRange(habit, byDay, period.StartDate, period.EndDate)
+	totalCompletions, totalAmount := history.totalsInRange(habit, period.StartDate, period.EndDate)
+
+	var averageAmount float64
+	if scheduledDays > 0 {
+		averageAmount = totalAmount / float64(scheduledDays)
+	}
 
 	var completionRate float64
 	if scheduledDays > 0 {
@@ -187,11 +428,13 @@ func calculateStatsForHabit(habit models.Habit, completions []models.Completion,
 		}
 	}
 
-	currentStreak, longestStreak := getHabitStreak(habit, completions, time.Now())
+	currentStreak, longestStreak := streaksFromDays(habit, byDay, pauses, today())
 
 	return habitStats{
 		Habit:            habit,
 		TotalCompletions: totalCompletions,
+		TotalAmount:      totalAmount,
+		AverageAmount:    averageAmount,
 		GoalDaysMet:      goalDaysMet,
 		ScheduledDays:    scheduledDays,
 		CompletionRate:   completionRate,
@@ -200,6 +443,31 @@ func calculateStatsForHabit(habit models.Habit, completions []models.Completion,
 	}
 }
 
+// calculateStatsForGroup aggregates habits (e.g. everything with one tag):
+// scheduled and met days are summed, and the streak is the group streak.
+func calculateStatsForGroup(habits []models.Habit, history habitHistory, pauses models.Pauses, period statsPeriod) habitStats {
+	var stats habitStats
+	for _, h := range habits {
+		p := periodForHabit(h, period)
+		count, _ := history.totalsInRange(h, p.StartDate, p.EndDate)
+		stats.ScheduledDays += countScheduledDaysInRange(h, pauses, p.StartDate, p.EndDate)
+		stats.GoalDaysMet += countGoalDaysMetInRange(h, history.amounts(h), p.StartDate, p.EndDate)
+		stats.TotalCompletions += count
+	}
+	if stats.ScheduledDays > 0 {
+		stats.CompletionRate = min(100, float64(stats.GoalDaysMet)/float64(stats.ScheduledDays)*100)
+	}
+	stats.CurrentStreak, stats.LongestStreak = getGroupStreak(habits, history, pauses, today())
+	return stats
+}
+
 func formatRate(rate float64) string {
 	return fmt.Sprintf("%.0f%%", rate)
 }
+
+func formatQuantity(amount float64, unit string) string {
+	if unit == "" {
+		return models.FormatAmount(amount)
+	}
+	return models.FormatAmount(amount) + " " + unit
+}
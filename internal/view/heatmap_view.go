@@ -0,0 +1,286 @@
+package view
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/bShaak/habitui/internal/models"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lucasb-eyer/go-colorful"
+)
+
+const (
+	heatmapWeeks  = 52
+	heatmapLevels = 5 // 0 = nothing logged … 4 = every due habit met
+)
+
+// heatmapRowLabel names every other weekday row, starting with the first.
+func heatmapRowLabel(row int) string {
+	if row%2 != 0 {
+		return ""
+	}
+	return weekdayOrder()[row].String()[:3]
+}
+
+// heatmapStart is the first day of the 52-week window that ends on end's week.
+func heatmapStart(end time.Time) time.Time {
+	return startOfWeek(end).AddDate(0, 0, -7*(heatmapWeeks-1))
+}
+
+// openHeatmap shows the year heatmap; habitIdx 0 combines all habits and
+// i > 0 selects m.habits[i-1].
+func openHeatmap(m Model, habitIdx int) Model {
+	m.statusMsg = ""
+	m.heatmapHabit = habitIdx
+	m.heatmapEnd = today()
+	m = loadHeatmapCompletions(m)
+	m.scrollOffset = 0
+	m.screen = screenHeatmap
+	return m
+}
+
+func loadHeatmapCompletions(m Model) Model {
+	start := heatmapStart(m.heatmapEnd)
+	completions, err := m.store.GetCompletionsByDateRange(context.Background(), start, m.heatmapEnd)
+	if err != nil {
+		log.Printf("Error fetching heatmap completions: %s", err)
+		return m
+	}
+	m.heatmapCompletions = completions
+	return m
+}
+
+func updateHeatmap(m Model, msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, keys.Quit):
+			return m, tea.Quit
+		case key.Matches(msg, keys.Down):
+			// Index 0 is "All habits"; 1..n map to m.habits.
+			if m.heatmapHabit < len(m.habits) {
+				m.heatmapHabit++
+			}
+		case key.Matches(msg, keys.Up):
+			if m.heatmapHabit > 0 {
+				m.heatmapHabit--
+			}
+		case key.Matches(msg, keys.Prev):
+			m.heatmapEnd = m.heatmapEnd.AddDate(-1, 0, 0)
+			m = loadHeatmapCompletions(m)
+		case key.Matches(msg, keys.Next):
+			today := today()
+			if !m.heatmapEnd.Before(today) {
+				return m, nil
+			}
+			next := m.heatmapEnd.AddDate(1, 0, 0)
+			if next.After(today) {
+				next = today
+			}
+			m.heatmapEnd = next
+			m = loadHeatmapCompletions(m)
+		}
+	}
+	return m, nil
+}
+
+// heatmapHabits returns the habits feeding the current heatmap.
+func (m Model) heatmapHabits() []models.Habit {
+	if m.heatmapHabit > 0 && m.heatmapHabit <= len(m.habits) {
+		return m.habits[m.heatmapHabit-1 : m.heatmapHabit]
+	}
+	return m.habits
+}
+
+// dayFulfilment returns how much of habit's target was reached on day (capped at 1)
+// and whether the day was due. Days before the habit started don't count.
+func dayFulfilment(habit models.Habit, byDay map[string]float64, pauses models.Pauses, day time.Time) (float64, bool) {
+	if start, ok := habitStartDay(habit); ok && day.Before(start) {
+		return 0, false
+	}
+	amount := byDay[day.Format(models.DayLayout)]
+	ratio := amount / habit.DailyTarget()
+	if ratio > 1 {
+		ratio = 1
+	}
+	if pauses.IsPaused(habit.ID, day) {
+		return ratio, false
+	}
+	due := habit.ScheduledOn(day)
+	if habit.Recurrence.IsWeeklyQuota() {
+		weekStart := startOfWeek(day)
+		due = amount > 0 || weekDaysMet(habit, byDay, weekStart) < weeklyQuota(habit, pauses, weekStart)
+	}
+	return ratio, due
+}
+
+// heatmapLevel maps a day's fulfilment across habits to an intensity 0-4.
+// Off-schedule check-ins count toward the day like they do for streaks.
+func heatmapLevel(habits []models.Habit, byDay []map[string]float64, pauses models.Pauses, day time.Time) int {
+	var total float64
+	due := 0
+	for i, h := range habits {
+		ratio, isDue := dayFulfilment(h, byDay[i], pauses, day)
+		if !isDue && ratio == 0 {
+			continue
+		}
+		due++
+		total += ratio
+	}
+	if due == 0 || total == 0 {
+		return 0
+	}
+	share := total / float64(due)
+	switch {
+	case share >= 1-1e-9:
+		return 4
+	case share > 0.5:
+		return 3
+	case share > 0.25:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// heatmapPalette blends from the theme surface toward accent. Non-hex colors
+// (e.g. ANSI indexes) fall back to surface for empty days and accent otherwise.
+func heatmapPalette(accent lipgloss.Color) []lipgloss.Color {
+	palette := make([]lipgloss.Color, heatmapLevels)
+	from, errFrom := colorful.Hex(string(surface))
+	to, errTo := colorful.Hex(string(accent))
+	for i := range palette {
+		switch {
+		case i == 0:
+			palette[i] = surface
+		case errFrom != nil || errTo != nil:
+			palette[i] = accent
+		default:
+			t := 0.25 + 0.75*float64(i-1)/float64(heatmapLevels-2)
+			palette[i] = lipgloss.Color(from.BlendLab(to, t).Clamped().Hex())
+		}
+	}
+	return palette
+}
+
+func viewHeatmap(m Model) string {
+	s := m.styles
+	var b strings.Builder
+	b.WriteString(m.renderTitle())
+	b.WriteString("\n")
+
+	start := heatmapStart(m.heatmapEnd)
+	header := fmt.Sprintf("Year in Review: %s – %s",
+		start.Format("Jan 2, 2006"), m.heatmapEnd.Format("Jan 2, 2006"))
+	b.WriteString(m.appBoundaryView(m.withHabitFilters(header)))
+	b.WriteString("\n\n")
+
+	var content strings.Builder
+	habits := m.heatmapHabits()
+	title := "All habits"
+	accent := green
+	if m.heatmapHabit > 0 && len(habits) == 1 {
+		title = habits[0].Label()
+		accent = getHabitColor(habits[0].Color)
+	}
+	content.WriteString(lipgloss.NewStyle().Foreground(accent).Bold(true).Render(title))
+	content.WriteString("\n\n")
+
+	if len(m.habits) == 0 {
+		content.WriteString(s.Help.Render(fmt.Sprintf("No habits created yet.\n\nPress '%s' from main view to create a new one.", helpKey(keys.Add))))
+		content.WriteString("\n\n")
+	} else {
+		content.WriteString(renderHeatmapGrid(habits, m.heatmapCompletions, m.pauses, start, m.heatmapEnd, heatmapWeeks, accent))
+		content.WriteString("\n")
+		content.WriteString(renderHeatmapSummary(habits, m.heatmapCompletions, m.pauses, start, m.heatmapEnd))
+		content.WriteString("\n\n")
+	}
+
+	content.WriteString(m.shortHelp())
+	b.WriteString(s.ContentBox.Render(content.String()))
+	return s.Base.Render(b.String())
+}
+
+// renderHeatmapGrid draws weeks columns of days from start, leaving days
+// after end blank.
+func renderHeatmapGrid(habits []models.Habit, completions []models.Completion, pauses models.Pauses, start, end time.Time, weeks int, accent lipgloss.Color) string {
+	byDay := make([]map[string]float64, len(habits))
+	for i, h := range habits {
+		byDay[i] = amountsByDay(completions, h)
+	}
+	palette := heatmapPalette(accent)
+	cellStyles := make([]lipgloss.Style, len(palette))
+	for i, c := range palette {
+		cellStyles[i] = lipgloss.NewStyle().Foreground(c)
+	}
+	labelStyle := lipgloss.NewStyle().Foreground(muted).Width(4)
+
+	var out strings.Builder
+
+	// Month labels sit above the first week that starts in a new month.
+	months := []rune(strings.Repeat(" ", weeks+3))
+	lastMonth := time.Month(0)
+	for w := 0; w < weeks; w++ {
+		weekStart := start.AddDate(0, 0, 7*w)
+		if weekStart.Month() != lastMonth {
+			lastMonth = weekStart.Month()
+			for i, r := range weekStart.Format("Jan") {
+				if w+i < len(months) {
+					months[w+i] = r
+				}
+			}
+		}
+	}
+	out.WriteString(labelStyle.Render(""))
+	out.WriteString(lipgloss.NewStyle().Foreground(subtext).Render(strings.TrimRight(string(months), " ")))
+	out.WriteString("\n")
+
+	for row := 0; row < 7; row++ {
+		out.WriteString(labelStyle.Render(heatmapRowLabel(row)))
+		for w := 0; w < weeks; w++ {
+			day := start.AddDate(0, 0, 7*w+row)
+			if day.After(end) {
+				out.WriteString(" ")
+				continue
+			}
+			level := heatmapLevel(habits, byDay, pauses, day)
+			out.WriteString(cellStyles[level].Render("■"))
+		}
+		out.WriteString("\n")
+	}
+
+	out.WriteString("\n")
+	out.WriteString(labelStyle.Render(""))
+	out.WriteString(lipgloss.NewStyle().Foreground(muted).Render("Less "))
+	for _, st := range cellStyles {
+		out.WriteString(st.Render("■"))
+	}
+	out.WriteString(lipgloss.NewStyle().Foreground(muted).Render(" More"))
+	out.WriteString("\n")
+	return out.String()
+}
+
+func renderHeatmapSummary(habits []models.Habit, completions []models.Completion, pauses models.Pauses, start, end time.Time) string {
+	activeDays := make(map[string]bool)
+	goalDays := 0
+	for _, h := range habits {
+		byDay := amountsByDay(completions, h)
+		for key, amount := range byDay {
+			if amount > 0 {
+				activeDays[key] = true
+			}
+		}
+		goalDays += countGoalDaysMetInRange(h, byDay, start, end)
+	}
+	summary := fmt.Sprintf("%d active days  |  %d goal days met", len(activeDays), goalDays)
+	if len(habits) == 1 {
+		current, longest := getHabitStreak(habits[0], completions, pauses, end)
+		summary += fmt.Sprintf("  |  streak %d (best %d in range)", current, longest)
+	}
+	return lipgloss.NewStyle().Foreground(subtext).Render(summary)
+}
@@ -0,0 +1,393 @@
+package view
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// helpChrome is the width taken by Base padding and the ContentBox border,
+// padding and margin around a screen's help line.
+const helpChrome = 11
+
+// screenKeys is a screen's help.KeyMap: the footer shows short, the ? overlay
+// shows full plus the bindings that work everywhere.
+type screenKeys struct {
+	title string
+	short []key.Binding
+	full  [][]key.Binding
+}
+
+func (k screenKeys) ShortHelp() []key.Binding  { return k.short }
+func (k screenKeys) FullHelp() [][]key.Binding { return k.full }
+
+// describe returns a copy of b labelled for the current screen.
+func describe(b key.Binding, desc string) key.Binding {
+	b.SetHelp(b.Help().Key, desc)
+	return b
+}
+
+// pair merges two bindings into one help entry, e.g. "j/k: navigate".
+func pair(desc string, a, b key.Binding) key.Binding {
+	merged := key.NewBinding(
+		key.WithKeys(append(append([]string{}, a.Keys()...), b.Keys()...)...),
+		key.WithHelp(helpKey(a, b), desc),
+	)
+	return enabled(merged, a.Enabled() || b.Enabled())
+}
+
+// enabled returns a copy of b that is hidden from help when on is false.
+func enabled(b key.Binding, on bool) key.Binding {
+	b.SetEnabled(on)
+	return b
+}
+
+// keyMap returns the bindings active on the current screen.
+func (m Model) keyMap() screenKeys {
+	switch m.screen {
+	case screenCalendar:
+		return calendarKeys(m)
+	case screenStats:
+		return statsKeys()
+	case screenArchive:
+		return archiveKeys(m)
+	case screenHeatmap:
+		return heatmapKeys()
+	case screenVacations:
+		return vacationKeys(m)
+	case screenThemes:
+		return themeKeys()
+	case screenDayLog:
+		return dayLogKeys(m)
+	case screenHabitDetail:
+		return habitDetailKeys(m)
+	default:
+		return mainKeys(m)
+	}
+}
+
+func mainKeys(m Model) screenKeys {
+	has := len(m.habits) > 0
+	toggle := enabled(describe(keys.Toggle, "toggle"), has)
+	edit := enabled(describe(keys.Edit, "edit"), has)
+	return screenKeys{
+		title: "Habits",
+		short: []key.Binding{
+			toggle,
+			describe(keys.Add, "add"),
+			edit,
+			describe(keys.Calendar, "calendar"),
+			describe(keys.Stats, "stats"),
+			describe(keys.Search, "filter"),
+			describe(keys.Help, "more"),
+			describe(keys.Quit, "quit"),
+		},
+		full: [][]key.Binding{
+			{
+				enabled(pair("navigate", keys.Down, keys.Up), has),
+				enabled(pair("move", keys.MoveDown, keys.MoveUp), has),
+				toggle,
+				enabled(pair("one more/less", keys.Increment, keys.Decrement), has),
+				describe(keys.Add, "add"),
+				edit,
+				enabled(describe(keys.Details, "details"), has),
+				enabled(describe(keys.Archive, "archive"), has),
+				enabled(describe(keys.Delete, "delete"), has),
+			},
+			{
+				describe(keys.Calendar, "calendar"),
+				describe(keys.Stats, "stats"),
+				describe(keys.Heatmap, "year"),
+				describe(keys.Archived, "archived"),
+				describe(keys.Vacations, "vacations"),
+				describe(keys.DayLog, "day log"),
+			},
+			{
+				describe(keys.Sort, "sort"),
+				describe(keys.TagFilter, "tag"),
+				describe(keys.Search, "filter"),
+				describe(keys.Theme, "theme"),
+			},
+		},
+	}
+}
+
+func calendarKeys(m Model) screenKeys {
+	has := len(m.habits) > 0
+	return screenKeys{
+		title: "Calendar",
+		short: []key.Binding{
+			pair("days", keys.Left, keys.Right),
+			enabled(pair("habits", keys.Down, keys.Up), has),
+			enabled(describe(keys.Toggle, "toggle"), has),
+			enabled(describe(keys.SkipHabit, "skip"), has),
+			describe(keys.Help, "more"),
+			describe(keys.Back, "back"),
+		},
+		full: [][]key.Binding{
+			{
+				pair("navigate days", keys.Left, keys.Right),
+				enabled(pair("navigate habits", keys.Down, keys.Up), has),
+				pair("prev/next week", keys.Prev, keys.Next),
+			},
+			{
+				enabled(describe(keys.Toggle, "toggle"), has),
+				enabled(pair("one more/less", keys.Increment, keys.Decrement), has),
+				enabled(describe(keys.SkipHabit, "skip habit"), has),
+				describe(keys.SkipAll, "skip all"),
+				describe(keys.DayLog, "day log"),
+				enabled(describe(keys.Details, "details"), has),
+			},
+			{
+				describe(keys.Heatmap, "year"),
+				describe(keys.TagFilter, "tag"),
+				describe(keys.Search, "filter"),
+			},
+		},
+	}
+}
+
+func statsKeys() screenKeys {
+	return screenKeys{
+		title: "Stats",
+		short: []key.Binding{
+			pair("tabs", keys.PrevTab, keys.NextTab),
+			pair("period", keys.Prev, keys.Next),
+			describe(keys.CustomRange, "range"),
+			describe(keys.Help, "more"),
+			describe(keys.Back, "back"),
+		},
+		full: [][]key.Binding{
+			{
+				pair("switch tabs", keys.PrevTab, keys.NextTab),
+				pair("prev/next period", keys.Prev, keys.Next),
+				describe(keys.CustomRange, "custom range"),
+			},
+			{
+				describe(keys.TagFilter, "tag"),
+			},
+		},
+	}
+}
+
+func archiveKeys(m Model) screenKeys {
+	has := len(m.archivedHabits) > 0
+	restore := enabled(describe(keys.Restore, "restore"), has)
+	remove := enabled(describe(keys.Delete, "delete forever"), has)
+	details := enabled(describe(keys.Details, "details"), has)
+	return screenKeys{
+		title: "Archived Habits",
+		short: []key.Binding{
+			enabled(pair("navigate", keys.Down, keys.Up), has),
+			details,
+			restore,
+			remove,
+			describe(keys.Help, "more"),
+			describe(keys.Back, "back"),
+		},
+		full: [][]key.Binding{
+			{enabled(pair("navigate", keys.Down, keys.Up), has), details, restore, remove},
+		},
+	}
+}
+
+func heatmapKeys() screenKeys {
+	return screenKeys{
+		title: "Year in Review",
+		short: []key.Binding{
+			pair("habit", keys.Down, keys.Up),
+			pair("year", keys.Prev, keys.Next),
+			describe(keys.Help, "more"),
+			describe(keys.Back, "back"),
+		},
+		full: [][]key.Binding{
+			{pair("switch habit", keys.Down, keys.Up), pair("prev/next year", keys.Prev, keys.Next)},
+		},
+	}
+}
+
+func vacationKeys(m Model) screenKeys {
+	has := len(m.vacations) > 0
+	remove := enabled(describe(keys.Delete, "delete"), has)
+	return screenKeys{
+		title: "Vacations",
+		short: []key.Binding{
+			enabled(pair("navigate", keys.Down, keys.Up), has),
+			describe(keys.Add, "add"),
+			remove,
+			describe(keys.Help, "more"),
+			describe(keys.Back, "back"),
+		},
+		full: [][]key.Binding{
+			{enabled(pair("navigate", keys.Down, keys.Up), has), describe(keys.Add, "add"), remove},
+		},
+	}
+}
+
+func dayLogKeys(m Model) screenKeys {
+	has := m.dayLog != nil && len(m.dayLog.entries) > 0
+	edit := enabled(describe(keys.Edit, "edit time"), has)
+	remove := enabled(describe(keys.Delete, "delete"), has)
+	return screenKeys{
+		title: "Day Log",
+		short: []key.Binding{
+			enabled(pair("navigate", keys.Down, keys.Up), has),
+			pair("days", keys.Left, keys.Right),
+			describe(keys.Add, "add"),
+			edit,
+			remove,
+			describe(keys.Help, "more"),
+			describe(keys.Back, "back"),
+		},
+		full: [][]key.Binding{
+			{enabled(pair("navigate", keys.Down, keys.Up), has), pair("previous/next day", keys.Left, keys.Right)},
+			{describe(keys.Add, "add"), edit, remove},
+		},
+	}
+}
+
+func habitDetailKeys(m Model) screenKeys {
+	active := m.detail == nil || m.detail.habit.ArchivedAt == ""
+	edit := enabled(describe(keys.Edit, "edit"), active)
+	archive := enabled(describe(keys.Archive, "archive"), active)
+	calendar := enabled(describe(keys.Calendar, "calendar"), active)
+	return screenKeys{
+		title: "Habit Details",
+		short: []key.Binding{
+			pair("habit", keys.Down, keys.Up),
+			edit,
+			archive,
+			calendar,
+			describe(keys.Help, "more"),
+			describe(keys.Back, "back"),
+		},
+		full: [][]key.Binding{
+			{pair("next/previous habit", keys.Down, keys.Up)},
+			{edit, archive, calendar},
+		},
+	}
+}
+
+func themeKeys() screenKeys {
+	return screenKeys{
+		title: "Themes",
+		short: []key.Binding{
+			pair("preview", keys.Down, keys.Up),
+			describe(keys.Toggle, "apply"),
+			describe(keys.Back, "cancel"),
+		},
+		full: [][]key.Binding{
+			{pair("preview theme", keys.Down, keys.Up), describe(keys.Toggle, "apply and save"), describe(keys.Back, "cancel")},
+		},
+	}
+}
+
+// globalKeys work on every screen and close out the overlay.
+func globalKeys() []key.Binding {
+	return []key.Binding{
+		describe(keys.PageUp, "scroll up"),
+		describe(keys.PageDown, "scroll down"),
+		describe(keys.Help, "toggle help"),
+		describe(keys.Back, "back"),
+		describe(keys.Quit, "quit"),
+	}
+}
+
+// helpModel styles bubbles/help with the current theme. Built per render so a
+// theme switch applies immediately.
+func (m Model) helpModel() help.Model {
+	h := help.New()
+	keyStyle := lipgloss.NewStyle().Foreground(subtext)
+	descStyle := lipgloss.NewStyle().Foreground(muted)
+	h.Styles = help.Styles{
+		Ellipsis:       descStyle,
+		ShortKey:       keyStyle,
+		ShortDesc:      descStyle,
+		ShortSeparator: descStyle,
+		FullKey:        keyStyle,
+		FullDesc:       descStyle,
+		FullSeparator:  descStyle,
+	}
+	if m.width > helpChrome {
+		h.Width = m.width - helpChrome
+	}
+	return h
+}
+
+// shortHelp renders the current screen's footer, truncated to fit.
+func (m Model) shortHelp() string {
+	return m.helpModel().ShortHelpView(m.keyMap().ShortHelp())
+}
+
+func openHelp(m Model) Model {
+	m.showHelp = true
+	m.scrollOffset = 0
+	return m
+}
+
+// updateHelp handles keys while the overlay is open; anything that isn't a
+// close or scroll key is swallowed so it can't act on the hidden screen.
+func updateHelp(m Model, msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, keys.Help, keys.Back):
+		m.showHelp = false
+		m.scrollOffset = 0
+	case key.Matches(msg, keys.Quit):
+		return m, tea.Quit
+	case key.Matches(msg, keys.PageUp):
+		m.scrollOffset = clampScroll(m.scrollOffset-pageScrollAmount(m), 0, maxScroll(m))
+	case key.Matches(msg, keys.PageDown):
+		m.scrollOffset = clampScroll(m.scrollOffset+pageScrollAmount(m), 0, maxScroll(m))
+	}
+	return m, nil
+}
+
+// viewHelp is the full-screen overlay. Columns sit side by side when they fit
+// and stack otherwise, so no binding is cut off on narrow terminals.
+func viewHelp(m Model) string {
+	s := m.styles
+	km := m.keyMap()
+	h := m.helpModel()
+	h.Width = 0
+
+	groups := append(km.FullHelp(), globalKeys())
+	columns := make([]string, 0, len(groups))
+	for _, g := range groups {
+		if col := h.FullHelpView([][]key.Binding{g}); col != "" {
+			columns = append(columns, col)
+		}
+	}
+	var body string
+	wide := lipgloss.JoinHorizontal(lipgloss.Top, intersperse(columns, "    ")...)
+	if m.width <= 0 || lipgloss.Width(wide)+helpChrome <= m.width {
+		body = wide
+	} else {
+		body = strings.Join(columns, "\n\n")
+	}
+
+	var b strings.Builder
+	b.WriteString(m.renderTitle())
+	b.WriteString("\n")
+	b.WriteString(m.appBoundaryView("Keys: " + km.title))
+	b.WriteString("\n\n")
+	var content strings.Builder
+	content.WriteString(body)
+	content.WriteString("\n\n")
+	content.WriteString(s.Help.Render(helpLine(helpItem("close", keys.Help, keys.Back))))
+	b.WriteString(s.ContentBox.Render(content.String()))
+	return s.Base.Render(b.String())
+}
+
+func intersperse(items []string, sep string) []string {
+	out := make([]string, 0, 2*len(items))
+	for i, item := range items {
+		if i > 0 {
+			out = append(out, sep)
+		}
+		out = append(out, item)
+	}
+	return out
+}
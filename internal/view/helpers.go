@@ -7,15 +7,16 @@ import (
 	"github.com/bShaak/habitui/internal/models"
 )
 
-var allWeekdays = []string{
-	"monday", "tuesday", "wednesday", "thursday", "friday", "saturday", "sunday",
-}
+var allWeekdays = models.Weekdays
 
 func effectiveGoal(goal int) int {
-	if goal < 1 {
-		return 1
-	}
-	return goal
+	return models.EffectiveGoal(goal)
+}
+
+// today is midnight of the current day. Days end at the configured rollover,
+// so shortly after midnight this is still yesterday's date.
+func today() time.Time {
+	return models.HabitDay(time.Now())
 }
 
 func startOfDay(t time.Time) time.Time {
@@ -26,27 +27,9 @@ func endOfDay(t time.Time) time.Time {
 	return time.Date(t.Year(), t.Month(), t.Day(), 23, 59, 59, 999999999, t.Location())
 }
 
-func inDayBounds(t, day time.Time) bool {
-	start, end := startOfDay(day), endOfDay(day)
-	return (t.Equal(start) || t.After(start)) && (t.Equal(end) || t.Before(end))
-}
-
 // normalizeFrequency stores empty or all-days schedules as "daily".
 func normalizeFrequency(days []string) string {
-	cleaned := make([]string, 0, len(days))
-	seen := make(map[string]bool, len(days))
-	for _, d := range days {
-		d = strings.ToLower(strings.TrimSpace(d))
-		if d == "" || d == "daily" || seen[d] {
-			continue
-		}
-		seen[d] = true
-		cleaned = append(cleaned, d)
-	}
-	if len(cleaned) == 0 || len(cleaned) == 7 {
-		return "daily"
-	}
-	return strings.Join(cleaned, ",")
+	return models.NormalizeFrequency(days)
 }
 
 // frequencyDaysForForm expands "daily" into all weekdays so the multi-select shows a full schedule.
@@ -94,69 +77,78 @@ func truncateRunes(s string, max int) string {
 	return string(runes[:max-1]) + "…"
 }
 
-func parseFrequency(frequency string) map[string]bool {
-	days := make(map[string]bool)
-	for _, d := range strings.Split(strings.ToLower(frequency), ",") {
-		d = strings.TrimSpace(d)
-		if d != "" {
-			days[d] = true
-		}
-	}
-	return days
-}
-
-func getDayName(t time.Time) string {
-	return strings.ToLower(t.Weekday().String())
-}
-
 func isScheduledOnDay(frequency string, dayName string) bool {
-	if frequency == "" || strings.ToLower(frequency) == "daily" {
-		return true
-	}
-	days := parseFrequency(frequency)
-	return days[dayName]
+	return models.IsScheduledOnDay(frequency, dayName)
 }
 
 func getCompletionsForHabitAndDate(completions []models.Completion, habitID int64, date time.Time) int {
 	count := 0
+	day := models.DayKey(date)
 	for _, c := range completions {
-		if c.HabitID != habitID {
-			continue
-		}
-		completedAt, err := time.Parse(time.RFC3339, c.CompletedAt)
-		if err != nil {
-			continue
-		}
-		if inDayBounds(completedAt, date) {
+		if c.HabitID == habitID && c.Day() == day {
 			count++
 		}
 	}
 	return count
 }
 
+// getAmountForHabitAndDate sums what habit logged on date: check-ins for
+// count habits, entered values for quantity habits.
+func getAmountForHabitAndDate(completions []models.Completion, habit models.Habit, date time.Time) float64 {
+	var amount float64
+	day := models.DayKey(date)
+	for _, c := range completions {
+		if c.HabitID == habit.ID && c.Day() == day {
+			amount += habit.CompletionAmount(c)
+		}
+	}
+	return amount
+}
+
 func isCompleted(completions []models.Completion, h models.Habit) bool {
-	return todayCompletionCount(completions, h.ID) >= effectiveGoal(h.Goal)
+	return h.TargetMet(todayAmount(completions, h))
 }
 
-func todayCompletionCount(completions []models.Completion, habitID int64) int {
-	count := 0
+// todayAmount sums habit's entries in a list that only holds today's completions.
+func todayAmount(completions []models.Completion, habit models.Habit) float64 {
+	var amount float64
 	for _, c := range completions {
-		if c.HabitID == habitID {
-			count++
+		if c.HabitID == habit.ID {
+			amount += habit.CompletionAmount(c)
 		}
 	}
-	return count
+	return amount
 }
 
-func formatHabitLabel(habit models.Habit) string {
-	if habit.Icon != "" {
-		return habit.Icon + " " + habit.Name
+// weekStarts maps the week_start config values to the day a week opens on.
+var weekStarts = map[string]time.Weekday{
+	"monday":   time.Monday,
+	"sunday":   time.Sunday,
+	"saturday": time.Saturday,
+}
+
+// firstWeekday is the configured first day of the week, Monday by default.
+func firstWeekday() time.Weekday {
+	if themeConfig != nil {
+		if d, ok := weekStarts[strings.ToLower(themeConfig.WeekStart)]; ok {
+			return d
+		}
 	}
-	return habit.Name
+	return time.Monday
+}
+
+// weekdayOrder lists the seven weekdays starting at firstWeekday.
+func weekdayOrder() []time.Weekday {
+	first := firstWeekday()
+	days := make([]time.Weekday, 7)
+	for i := range days {
+		days[i] = (first + time.Weekday(i)) % 7
+	}
+	return days
 }
 
-func getMonday(t time.Time) time.Time {
-	weekday := t.Weekday()
-	daysSinceMonday := (int(weekday) + 6) % 7
-	return startOfDay(t.AddDate(0, 0, -daysSinceMonday))
+// startOfWeek returns midnight on the first day of t's week.
+func startOfWeek(t time.Time) time.Time {
+	daysSinceStart := (int(t.Weekday()) - int(firstWeekday()) + 7) % 7
+	return startOfDay(t.AddDate(0, 0, -daysSinceStart))
 }
@@ -1,11 +1,19 @@
 package view
 
 import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 	"unicode/utf8"
 
 	"github.com/bShaak/habitui/internal/models"
+	"github.com/bShaak/habitui/internal/storage"
+	"github.com/bShaak/habitui/internal/theme"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 )
 
 func TestNormalizeFrequency(t *testing.T) {
@@ -95,7 +103,7 @@ func TestGetHabitStreak(t *testing.T) {
 		// today incomplete — current streak should still be 2
 	}
 
-	current, longest := getHabitStreak(habit, completions, today)
+	current, longest := getHabitStreak(habit, completions, models.Pauses{}, today)
 	if current != 2 {
 		t.Fatalf("current streak = %d, want 2", current)
 	}
@@ -118,7 +126,7 @@ func TestGetHabitStreakCountsOffScheduleCompletion(t *testing.T) {
 		{HabitID: 1, CompletedAt: time.Date(2026, 7, 10, 12, 51, 0, 0, loc).Format(time.RFC3339)},
 	}
 
-	current, longest := getHabitStreak(habit, completions, today)
+	current, longest := getHabitStreak(habit, completions, models.Pauses{}, today)
 	if current != 1 {
 		t.Fatalf("current streak = %d, want 1 (off-schedule completion should count)", current)
 	}
@@ -131,7 +139,7 @@ func TestGetHabitStreakCountsOffScheduleCompletion(t *testing.T) {
 		StartDate: startOfDay(today).AddDate(0, 0, -6),
 		EndDate:   endOfDay(today),
 	}
-	stats := calculateStatsForHabit(habit, completions, period)
+	stats := calculateStatsForHabit(habit, historyFromCompletions(completions), models.Pauses{}, period)
 	if stats.GoalDaysMet != 1 {
 		t.Fatalf("GoalDaysMet = %d, want 1", stats.GoalDaysMet)
 	}
@@ -153,7 +161,7 @@ func TestGetHabitStreakSkipsUnscheduledDays(t *testing.T) {
 		{HabitID: 1, CompletedAt: time.Date(2026, 7, 10, 9, 0, 0, 0, loc).Format(time.RFC3339)}, // Fri
 	}
 
-	current, longest := getHabitStreak(habit, completions, today)
+	current, longest := getHabitStreak(habit, completions, models.Pauses{}, today)
 	if current != 3 {
 		t.Fatalf("current streak = %d, want 3", current)
 	}
@@ -162,10 +170,10 @@ func TestGetHabitStreakSkipsUnscheduledDays(t *testing.T) {
 	}
 }
 
-func TestGetMondayNormalizesToMidnight(t *testing.T) {
+func TestStartOfWeekNormalizesToMidnight(t *testing.T) {
 	loc := time.Local
 	wednesday := time.Date(2026, 7, 8, 15, 45, 30, 0, loc)
-	monday := getMonday(wednesday)
+	monday := startOfWeek(wednesday)
 	if monday.Weekday() != time.Monday {
 		t.Fatalf("expected Monday, got %s", monday.Weekday())
 	}
@@ -174,6 +182,74 @@ func TestGetMondayNormalizesToMidnight(t *testing.T) {
 	}
 }
 
+func TestConfiguredWeekStart(t *testing.T) {
+	prev := themeConfig
+	defer func() { themeConfig = prev }()
+	wednesday := time.Date(2026, 7, 8, 15, 45, 30, 0, time.Local)
+	tests := []struct {
+		config string
+		want   time.Weekday
+		start  int // day of July 2026 the week opens on
+	}{
+		{config: "", want: time.Monday, start: 6},
+		{config: "sunday", want: time.Sunday, start: 5},
+		{config: "Saturday", want: time.Saturday, start: 4},
+		{config: "friday", want: time.Monday, start: 6},
+	}
+	for _, tt := range tests {
+		themeConfig = &theme.Config{WeekStart: tt.config}
+		got := startOfWeek(wednesday)
+		if got.Weekday() != tt.want || got.Day() != tt.start {
+			t.Fatalf("week_start %q: week of %s opens %s", tt.config, wednesday.Format("Mon Jan 2"), got.Format("Mon Jan 2"))
+		}
+		if startOfWeek(got) != got {
+			t.Fatalf("week_start %q: the first day should open its own week", tt.config)
+		}
+		if order := weekdayOrder(); order[0] != tt.want || order[6] != (tt.want+6)%7 {
+			t.Fatalf("week_start %q: order = %v", tt.config, order)
+		}
+	}
+
+	themeConfig = &theme.Config{WeekStart: "sunday"}
+	if heatmapRowLabel(0) != "Sun" || heatmapRowLabel(1) != "" || heatmapRowLabel(6) != "Sat" {
+		t.Fatalf("heatmap labels = %q … %q", heatmapRowLabel(0), heatmapRowLabel(6))
+	}
+	habits := []models.Habit{{ID: 1, Name: "Read"}}
+	m := Model{styles: newStyles(lipgloss.DefaultRenderer()), allHabits: habits, habits: habits, weekStart: startOfWeek(wednesday), search: newSearchInput(), screen: screenCalendar}
+	view, _ := renderCalendar(m)
+	if sun, sat := strings.Index(view, "Sun 5"), strings.Index(view, "Sat 11"); sun < 0 || sat < sun {
+		t.Fatalf("calendar should run Sun 5 … Sat 11:\n%s", view)
+	}
+}
+
+func TestCalendarShowsQuantityProgress(t *testing.T) {
+	weekStart := startOfWeek(time.Now())
+	at := weekStart.Add(9 * time.Hour).Format(time.RFC3339)
+	habits := []models.Habit{
+		{ID: 1, Name: "Read", Kind: models.HabitKindQuantity, Target: 30, Unit: "pages", StartDate: at},
+		{ID: 2, Name: "Steps", Kind: models.HabitKindQuantity, Target: 10000, StartDate: at},
+		{ID: 3, Name: "Water", Goal: 2, StartDate: at},
+	}
+	m := Model{
+		styles: newStyles(lipgloss.DefaultRenderer()), allHabits: habits, habits: habits, weekStart: weekStart, search: newSearchInput(), screen: screenCalendar,
+		weekCompletions: []models.Completion{
+			{HabitID: 1, CompletedAt: at, Value: 18},
+			{HabitID: 2, CompletedAt: at, Value: 12500},
+			{HabitID: 3, CompletedAt: at},
+		},
+	}
+	m.calendarCol = 6
+	view, _ := renderCalendar(m)
+	for _, want := range []string{"18/30", "12500", "✓"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("calendar is missing %q:\n%s", want, view)
+		}
+	}
+	if strings.Contains(view, "12500/") {
+		t.Errorf("an amount too wide for the cell should drop the target:\n%s", view)
+	}
+}
+
 func TestNeedsDayRefresh(t *testing.T) {
 	loc := time.Local
 	yesterday := time.Date(2026, 7, 14, 23, 0, 0, 0, loc)
@@ -190,3 +266,938 @@ func TestNeedsDayRefresh(t *testing.T) {
 		t.Fatal("same calendar day should not need refresh")
 	}
 }
+
+func TestDayEndsAtRollover(t *testing.T) {
+	defer models.SetDayRollover(0)
+	models.SetDayRollover(3 * time.Hour)
+	loc := time.Local
+	viewDay := time.Date(2026, 7, 14, 0, 0, 0, 0, loc)
+
+	if needsDayRefresh(viewDay, time.Date(2026, 7, 15, 1, 0, 0, 0, loc)) {
+		t.Fatal("01:00 is still the previous day before a 03:00 rollover")
+	}
+	if !needsDayRefresh(viewDay, time.Date(2026, 7, 15, 3, 0, 0, 0, loc)) {
+		t.Fatal("the day should refresh once the rollover passes")
+	}
+
+	// A 00:30 check-in after a late workout keeps the streak alive.
+	habit := models.Habit{ID: 1, Frequency: "daily", StartDate: time.Date(2026, 7, 1, 9, 0, 0, 0, loc).Format(time.RFC3339)}
+	var completions []models.Completion
+	for day := 10; day <= 14; day++ {
+		at := time.Date(2026, 7, day+1, 0, 30, 0, 0, loc).Format(time.RFC3339)
+		completions = append(completions, models.Completion{HabitID: 1, CompletedAt: at, LocalDate: models.CompletionDay(at)})
+	}
+	if current, _ := getHabitStreak(habit, completions, models.Pauses{}, viewDay); current != 5 {
+		t.Fatalf("streak = %d, want 5", current)
+	}
+}
+
+func TestQuantityHabitStatsAndStreak(t *testing.T) {
+	loc := time.Local
+	today := time.Date(2026, 7, 10, 12, 0, 0, 0, loc)
+	habit := models.Habit{
+		ID:        1,
+		Frequency: "daily",
+		Kind:      models.HabitKindQuantity,
+		Unit:      "pages",
+		Target:    30,
+		StartDate: time.Date(2026, 7, 1, 0, 0, 0, 0, loc).Format(time.RFC3339),
+	}
+	at := func(day, hour int, value float64) models.Completion {
+		return models.Completion{
+			HabitID:     1,
+			CompletedAt: time.Date(2026, 7, day, hour, 0, 0, 0, loc).Format(time.RFC3339),
+			Value:       value,
+		}
+	}
+	completions := []models.Completion{
+		at(8, 9, 12), at(8, 20, 18), // 30 — met across two entries
+		at(9, 9, 40),  // 40 — overshoot counts
+		at(10, 9, 18), // today partial
+	}
+
+	current, longest := getHabitStreak(habit, completions, models.Pauses{}, today)
+	if current != 2 || longest != 2 {
+		t.Fatalf("streak = %d/%d, want 2/2", current, longest)
+	}
+	if got := getAmountForHabitAndDate(completions, habit, today); got != 18 {
+		t.Fatalf("today amount = %v, want 18", got)
+	}
+	if got := habit.FormatProgress(18); got != "18/30 pages" {
+		t.Fatalf("FormatProgress = %q, want 18/30 pages", got)
+	}
+
+	period := statsPeriod{StartDate: startOfDay(today).AddDate(0, 0, -2), EndDate: endOfDay(today)}
+	stats := calculateStatsForHabit(habit, historyFromCompletions(completions), models.Pauses{}, period)
+	if stats.GoalDaysMet != 2 {
+		t.Fatalf("GoalDaysMet = %d, want 2", stats.GoalDaysMet)
+	}
+	if stats.TotalAmount != 88 {
+		t.Fatalf("TotalAmount = %v, want 88", stats.TotalAmount)
+	}
+	if stats.AverageAmount < 29.3 || stats.AverageAmount > 29.4 {
+		t.Fatalf("AverageAmount = %v, want ~29.33", stats.AverageAmount)
+	}
+}
+
+func TestWeeklyQuotaStreakAndStats(t *testing.T) {
+	loc := time.Local
+	// Wednesday Jul 15, 2026; weeks start Mon Jul 6 and Mon Jul 13.
+	today := time.Date(2026, 7, 15, 12, 0, 0, 0, loc)
+	habit := models.Habit{
+		ID:         1,
+		Frequency:  "daily",
+		Goal:       1,
+		Recurrence: models.Recurrence{Kind: models.RecurrenceWeekly, TimesPerWeek: 3},
+		StartDate:  time.Date(2026, 7, 6, 0, 0, 0, 0, loc).Format(time.RFC3339),
+	}
+	on := func(day int) models.Completion {
+		return models.Completion{HabitID: 1, CompletedAt: time.Date(2026, 7, day, 9, 0, 0, 0, loc).Format(time.RFC3339)}
+	}
+	// Last week: Mon, Wed, Sat (quota met). This week: Mon only so far.
+	completions := []models.Completion{on(6), on(8), on(11), on(13)}
+
+	current, longest := getHabitStreak(habit, completions, models.Pauses{}, today)
+	if current != 4 || longest != 4 {
+		t.Fatalf("streak = %d/%d, want 4/4 (off days in a met week must not break it)", current, longest)
+	}
+	if isDueOn(habit, completions, models.Pauses{}, time.Date(2026, 7, 12, 9, 0, 0, 0, loc)) {
+		t.Fatal("habit should not be due once the week's quota is met")
+	}
+	if !isDueOn(habit, completions, models.Pauses{}, today) {
+		t.Fatal("habit should be due while this week's quota is open")
+	}
+
+	period := statsPeriod{StartDate: time.Date(2026, 7, 6, 0, 0, 0, 0, loc), EndDate: endOfDay(today)}
+	stats := calculateStatsForHabit(habit, historyFromCompletions(completions), models.Pauses{}, period)
+	// Full week: 3 due; Mon-Wed of this week: min(3, 3 days) = 3.
+	if stats.ScheduledDays != 6 {
+		t.Fatalf("ScheduledDays = %d, want 6", stats.ScheduledDays)
+	}
+	if stats.GoalDaysMet != 4 {
+		t.Fatalf("GoalDaysMet = %d, want 4", stats.GoalDaysMet)
+	}
+
+	// A finished week that missed its quota breaks the streak.
+	missed := []models.Completion{on(6), on(13)}
+	current, _ = getHabitStreak(habit, missed, models.Pauses{}, today)
+	if current != 1 {
+		t.Fatalf("current streak after failed week = %d, want 1", current)
+	}
+}
+
+func TestHeatmapLevel(t *testing.T) {
+	loc := time.Local
+	mon := time.Date(2026, 7, 6, 0, 0, 0, 0, loc)
+	start := mon.AddDate(0, 0, -7).Format(time.RFC3339)
+	water := models.Habit{ID: 1, Frequency: "daily", Goal: 2, StartDate: start}
+	gym := models.Habit{ID: 2, Frequency: "monday", StartDate: start}
+	habits := []models.Habit{water, gym}
+	at := func(id int64, day int) models.Completion {
+		return models.Completion{HabitID: id, CompletedAt: time.Date(2026, 7, day, 9, 0, 0, 0, loc).Format(time.RFC3339)}
+	}
+	completions := []models.Completion{
+		at(1, 6), at(1, 6), at(2, 6), // Mon: both met
+		at(1, 7),           // Tue: water half done, gym not due
+		at(1, 8), at(2, 8), // Wed: water half, gym off-schedule counts
+	}
+	byDay := []map[string]float64{amountsByDay(completions, water), amountsByDay(completions, gym)}
+
+	tests := []struct {
+		day  int
+		want int
+	}{
+		{day: 6, want: 4},
+		{day: 7, want: 2},
+		{day: 8, want: 3},
+		{day: 9, want: 0},
+	}
+	for _, tt := range tests {
+		day := time.Date(2026, 7, tt.day, 0, 0, 0, 0, loc)
+		if got := heatmapLevel(habits, byDay, models.Pauses{}, day); got != tt.want {
+			t.Fatalf("heatmapLevel(Jul %d) = %d, want %d", tt.day, got, tt.want)
+		}
+	}
+	// A habit added on Wednesday doesn't count against the days before it.
+	read := models.Habit{ID: 3, Frequency: "daily", StartDate: time.Date(2026, 7, 8, 20, 0, 0, 0, loc).Format(time.RFC3339)}
+	late := []models.Habit{gym, read}
+	lateByDay := []map[string]float64{byDay[1], amountsByDay(nil, read)}
+	if got := heatmapLevel(late, lateByDay, models.Pauses{}, mon); got != 4 {
+		t.Fatalf("heatmapLevel(Jul 6) before Read started = %d, want 4", got)
+	}
+	if got := heatmapLevel(late, lateByDay, models.Pauses{}, mon.AddDate(0, 0, 2)); got != 2 {
+		t.Fatalf("heatmapLevel(Jul 8) once Read started = %d, want 2", got)
+	}
+	if got := heatmapStart(mon.AddDate(0, 0, 3)); !got.Equal(mon.AddDate(0, 0, -7*51)) || got.Weekday() != time.Monday {
+		t.Fatalf("heatmapStart = %v, want the Monday 51 weeks back", got)
+	}
+}
+
+func TestArrangeHabitsSortModes(t *testing.T) {
+	saved := themeConfig
+	t.Cleanup(func() { themeConfig = saved })
+
+	today := time.Now().Format(time.RFC3339)
+	m := Model{
+		allHabits: []models.Habit{
+			{ID: 1, Name: "A", Color: "pink", SortOrder: 1, StartDate: today},
+			{ID: 2, Name: "B", Color: "red", SortOrder: 2, StartDate: today},
+			{ID: 3, Name: "C", Color: "blue", SortOrder: 3, StartDate: today},
+		},
+		completions: []models.Completion{{HabitID: 1, CompletedAt: today}},
+		cursor:      2,
+	}
+	m.habits = m.allHabits
+	order := func(m Model) string {
+		var s string
+		for _, h := range m.habits {
+			s += h.Name
+		}
+		return s
+	}
+
+	themeConfig = &theme.Config{SortMode: sortIncomplete}
+	got := arrangeHabits(m)
+	if order(got) != "BCA" {
+		t.Fatalf("incomplete first = %s, want BCA", order(got))
+	}
+	if got.habits[got.cursor].ID != 3 {
+		t.Fatalf("cursor should stay on habit C, got %s", got.habits[got.cursor].Name)
+	}
+
+	themeConfig = &theme.Config{SortMode: sortColor}
+	if got := arrangeHabits(m); order(got) != "BCA" {
+		t.Fatalf("by color = %s, want BCA", order(got))
+	}
+
+	themeConfig = &theme.Config{}
+	m.allHabits[0].SortOrder = 4
+	if got := arrangeHabits(m); order(got) != "BCA" {
+		t.Fatalf("manual = %s, want BCA", order(got))
+	}
+
+	m.allHabits[2].Tags = []string{"health"}
+	m.tagFilter = "health"
+	if got := arrangeHabits(m); order(got) != "C" {
+		t.Fatalf("tag filter = %s, want C", order(got))
+	}
+	m.tagFilter = "gone"
+	if got := arrangeHabits(m); order(got) != "BCA" || got.tagFilter != "" {
+		t.Fatalf("stale tag filter should reset, got %s (filter %q)", order(got), got.tagFilter)
+	}
+}
+
+func TestGroupStreakNeedsEveryRequiredHabit(t *testing.T) {
+	loc := time.Local
+	today := time.Date(2026, 7, 10, 12, 0, 0, 0, loc)
+	start := time.Date(2026, 7, 6, 0, 0, 0, 0, loc).Format(time.RFC3339)
+	run := models.Habit{ID: 1, Frequency: "daily", StartDate: start}
+	lift := models.Habit{ID: 2, Frequency: "monday,wednesday,friday", StartDate: start}
+	on := func(id int64, day int) models.Completion {
+		return models.Completion{HabitID: id, CompletedAt: time.Date(2026, 7, day, 9, 0, 0, 0, loc).Format(time.RFC3339)}
+	}
+	// Run every day Mon-Fri; lift Mon and Fri but not Wed.
+	completions := []models.Completion{on(1, 6), on(1, 7), on(1, 8), on(1, 9), on(1, 10), on(2, 6), on(2, 10)}
+
+	current, longest := getGroupStreak([]models.Habit{run, lift}, historyFromCompletions(completions), models.Pauses{}, today)
+	if current != 2 || longest != 2 {
+		t.Fatalf("group streak = %d/%d, want 2/2 (missed lift on Wed)", current, longest)
+	}
+
+	period := statsPeriod{StartDate: startOfDay(today).AddDate(0, 0, -4), EndDate: endOfDay(today)}
+	stats := calculateStatsForGroup([]models.Habit{run, lift}, historyFromCompletions(completions), models.Pauses{}, period)
+	if stats.GoalDaysMet != 7 || stats.ScheduledDays != 8 {
+		t.Fatalf("group completed = %d/%d, want 7/8", stats.GoalDaysMet, stats.ScheduledDays)
+	}
+}
+
+func TestSearchFiltersHabits(t *testing.T) {
+	habits := []models.Habit{
+		{ID: 1, Name: "Meditate", SortOrder: 1},
+		{ID: 2, Name: "Read", Icon: "📚", Description: "fiction before bed", SortOrder: 2},
+		{ID: 3, Name: "Run", SortOrder: 3},
+	}
+	if !fuzzyMatch("mdt", "Meditate") || fuzzyMatch("tdm", "Meditate") {
+		t.Fatal("fuzzyMatch should match runes in order only")
+	}
+
+	m := Model{allHabits: habits, search: newSearchInput()}
+	tests := []struct {
+		query string
+		want  []int64
+	}{
+		{query: "r", want: []int64{2, 3}},
+		{query: "rn", want: []int64{3}},
+		{query: "📚", want: []int64{2}},
+		{query: "read bed", want: []int64{2}},
+		{query: "xyz", want: nil},
+	}
+	for _, tt := range tests {
+		m.search.SetValue(tt.query)
+		got := arrangeHabits(m)
+		var ids []int64
+		for _, h := range got.habits {
+			ids = append(ids, h.ID)
+		}
+		if fmt.Sprint(ids) != fmt.Sprint(tt.want) {
+			t.Fatalf("search %q = %v, want %v", tt.query, ids, tt.want)
+		}
+	}
+
+	m.search.SetValue("run")
+	m = arrangeHabits(m)
+	m = clearSearch(m)
+	if len(m.habits) != 3 || m.habits[m.cursor].ID != 3 {
+		t.Fatalf("clearing the search should restore all habits and keep the selection, got %d habits, cursor on %d", len(m.habits), m.habits[m.cursor].ID)
+	}
+
+	// Stats and the heatmap don't show the search line, so their headers name the filter.
+	m.search.SetValue("run")
+	m = arrangeHabits(m)
+	m.styles = newStyles(lipgloss.DefaultRenderer())
+	m.heatmapEnd = today()
+	if view := viewHeatmap(m); !strings.Contains(view, "/run") {
+		t.Fatalf("heatmap header should show the search filter:\n%s", view)
+	}
+	m.tagFilter = "health"
+	if got := m.withHabitFilters("Habit Statistics"); got != "Habit Statistics  #health  /run" {
+		t.Fatalf("withHabitFilters = %q", got)
+	}
+}
+
+func TestPausedDaysKeepStreaksAndRates(t *testing.T) {
+	loc := time.Local
+	today := time.Date(2026, 7, 10, 12, 0, 0, 0, loc)
+	habit := models.Habit{
+		ID:        1,
+		Frequency: "daily",
+		Goal:      1,
+		StartDate: time.Date(2026, 7, 1, 0, 0, 0, 0, loc).Format(time.RFC3339),
+	}
+	on := func(day int) models.Completion {
+		return models.Completion{HabitID: 1, CompletedAt: time.Date(2026, 7, day, 9, 0, 0, 0, loc).Format(time.RFC3339)}
+	}
+	// Done Jul 4-5 and 9-10, away Jul 6-7, Jul 8 skipped for this habit only.
+	completions := []models.Completion{on(4), on(5), on(9), on(10)}
+	pauses := models.NewPauses(
+		[]models.Skip{{HabitID: 1, Day: "2026-07-08"}},
+		[]models.Vacation{{StartDay: "2026-07-06", EndDay: "2026-07-07"}},
+	)
+
+	current, longest := getHabitStreak(habit, completions, pauses, today)
+	if current != 4 || longest != 4 {
+		t.Fatalf("streak = %d/%d, want 4/4 (paused days neither count nor break it)", current, longest)
+	}
+	if current, _ := getHabitStreak(habit, completions, models.Pauses{}, today); current != 2 {
+		t.Fatalf("streak without pauses = %d, want 2", current)
+	}
+
+	period := statsPeriod{StartDate: time.Date(2026, 7, 4, 0, 0, 0, 0, loc), EndDate: endOfDay(today)}
+	stats := calculateStatsForHabit(habit, historyFromCompletions(completions), pauses, period)
+	if stats.ScheduledDays != 4 || stats.GoalDaysMet != 4 || stats.CompletionRate != 100 {
+		t.Fatalf("stats = %d/%d at %.0f%%, want 4/4 at 100%%", stats.GoalDaysMet, stats.ScheduledDays, stats.CompletionRate)
+	}
+	if isDueOn(habit, completions, pauses, time.Date(2026, 7, 8, 9, 0, 0, 0, loc)) {
+		t.Fatal("a skipped day should not be due")
+	}
+
+	other := models.Habit{ID: 2, Frequency: "daily", Goal: 1, StartDate: habit.StartDate}
+	if !isDueOn(other, nil, pauses, time.Date(2026, 7, 8, 9, 0, 0, 0, loc)) {
+		t.Fatal("a single-habit skip should not pause other habits")
+	}
+}
+
+func TestStatsPeriodFor(t *testing.T) {
+	loc := time.Local
+	now := time.Date(2026, 3, 18, 15, 0, 0, 0, loc)
+	day := func(y int, mo time.Month, d int) time.Time { return time.Date(y, mo, d, 0, 0, 0, 0, loc) }
+
+	cases := []struct {
+		name       string
+		tab        int
+		offset     int
+		start, end time.Time
+	}{
+		{"this month so far", statsTabMonth, 0, day(2026, 3, 1), day(2026, 3, 18)},
+		{"month before last", statsTabMonth, 2, day(2026, 1, 1), day(2026, 1, 31)},
+		{"previous year", statsTabYear, 1, day(2025, 1, 1), day(2025, 12, 31)},
+		{"previous 7 days", statsTab7Days, 1, day(2026, 3, 5), day(2026, 3, 11)},
+	}
+	for _, tc := range cases {
+		p := statsPeriodFor(tc.tab, tc.offset, statsPeriod{}, nil, now)
+		if !p.StartDate.Equal(tc.start) || !startOfDay(p.EndDate).Equal(tc.end) {
+			t.Errorf("%s: got %s – %s, want %s – %s", tc.name,
+				p.StartDate.Format("2006-01-02"), p.EndDate.Format("2006-01-02"),
+				tc.start.Format("2006-01-02"), tc.end.Format("2006-01-02"))
+		}
+	}
+
+	custom := statsPeriod{StartDate: day(2026, 2, 1), EndDate: endOfDay(day(2026, 2, 10))}
+	p := statsPeriodFor(statsTabCustom, 1, custom, nil, now)
+	if !p.StartDate.Equal(day(2026, 1, 22)) || !startOfDay(p.EndDate).Equal(day(2026, 1, 31)) {
+		t.Errorf("custom offset 1 = %s – %s, want 2026-01-22 – 2026-01-31",
+			p.StartDate.Format("2006-01-02"), p.EndDate.Format("2006-01-02"))
+	}
+
+	// All time starts at each habit's own StartDate.
+	habit := models.Habit{ID: 1, Frequency: "daily", Goal: 1, StartDate: day(2026, 3, 9).Format(time.RFC3339)}
+	older := models.Habit{ID: 2, Frequency: "daily", Goal: 1, StartDate: day(2026, 1, 1).Format(time.RFC3339)}
+	all := statsPeriodFor(statsTabAllTime, 0, statsPeriod{}, []models.Habit{habit, older}, now)
+	if !all.StartDate.Equal(day(2026, 1, 1)) {
+		t.Fatalf("all time starts %s, want the oldest habit's start", all.StartDate.Format("2006-01-02"))
+	}
+	if stats := calculateStatsForHabit(habit, habitHistory{}, models.Pauses{}, all); stats.ScheduledDays != 10 {
+		t.Fatalf("all-time ScheduledDays = %d, want 10 (Mar 9–18)", stats.ScheduledDays)
+	}
+}
+
+func TestHelpReflectsActiveKeys(t *testing.T) {
+	initKeys(&theme.Config{Keys: map[string][]string{"add": {"N"}, "help": {"F"}}})
+	defer initKeys(nil)
+
+	m := Model{styles: newStyles(lipgloss.DefaultRenderer()), search: newSearchInput()}
+	short := m.shortHelp()
+	if !strings.Contains(short, "N add") || !strings.Contains(short, "F more") {
+		t.Fatalf("short help = %q, want overridden keys", short)
+	}
+	if strings.Contains(short, "edit") {
+		t.Fatalf("short help = %q, edit shown with no habits", short)
+	}
+
+	next, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("?")})
+	if next.(Model).showHelp {
+		t.Fatal("default help key still opens the overlay")
+	}
+	next, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("F")})
+	m = next.(Model)
+	if !m.showHelp {
+		t.Fatal("overridden help key did not open the overlay")
+	}
+	overlay := m.View()
+	for _, want := range []string{"Keys: Habits", "vacations", "toggle help"} {
+		if !strings.Contains(overlay, want) {
+			t.Fatalf("overlay missing %q:\n%s", want, overlay)
+		}
+	}
+
+	// Other keys are swallowed; esc closes.
+	next, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("N")})
+	if m = next.(Model); !m.showHelp || m.screen != screenMain {
+		t.Fatal("keys leaked through the overlay")
+	}
+	next, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if next.(Model).showHelp {
+		t.Fatal("esc did not close the overlay")
+	}
+}
+
+func TestStatusHintsUseActiveKeys(t *testing.T) {
+	initKeys(&theme.Config{Keys: map[string][]string{"sort": {"O"}}})
+	defer initKeys(nil)
+	saved := themeConfig
+	themeConfig = &theme.Config{SortMode: sortStreak}
+	defer func() { themeConfig = saved }()
+
+	habits := []models.Habit{{ID: 1, Name: "Meditate"}, {ID: 2, Name: "Read"}}
+	m := Model{styles: newStyles(lipgloss.DefaultRenderer()), allHabits: habits, habits: habits}
+	m.setStatus("Sort: Streak")
+	m = moveSelectedHabit(m, 1)
+	if !strings.Contains(m.statusMsg, "(O)") {
+		t.Fatalf("reorder hint = %q, want the remapped sort key", m.statusMsg)
+	}
+	if m.statusInfo {
+		t.Fatalf("reorder hint %q is shown as a confirmation, want an error", m.statusMsg)
+	}
+}
+
+func TestFormsAndSearchUseActiveConfirmKey(t *testing.T) {
+	initKeys(&theme.Config{Keys: map[string][]string{"confirm": {"y", "ctrl+s"}}})
+	defer initKeys(nil)
+
+	// "y" would type into the field, so only ctrl+s submits.
+	if got := submitKey().Keys(); fmt.Sprint(got) != "[ctrl+s]" {
+		t.Fatalf("submit keys = %v, want [ctrl+s]", got)
+	}
+	if got := formKeyMap().Input.Submit.Keys(); fmt.Sprint(got) != "[ctrl+s]" {
+		t.Fatalf("form submit keys = %v, want [ctrl+s]", got)
+	}
+
+	m := Model{styles: newStyles(lipgloss.DefaultRenderer()), search: newSearchInput()}
+	m, _ = openStatsRangeForm(m)
+	if view := viewStatsRange(m); !strings.Contains(view, "ctrl+s: next / apply") || strings.Contains(view, "enter:") {
+		t.Fatalf("stats range hint should show the remapped key:\n%s", view)
+	}
+
+	m.screen = screenMain
+	m, _ = startSearch(m)
+	m, _ = updateSearch(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	m, _ = updateSearch(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if !m.searching || m.searchQuery() != "y" {
+		t.Fatalf("search should type y and ignore enter, got searching=%v query %q", m.searching, m.searchQuery())
+	}
+	m, _ = updateSearch(m, tea.KeyMsg{Type: tea.KeyCtrlS})
+	if m.searching || m.searchQuery() != "y" {
+		t.Fatal("ctrl+s should keep the filter and leave the search input")
+	}
+}
+
+func TestMouseZonesMatchRenderedText(t *testing.T) {
+	habits := []models.Habit{{ID: 1, Name: "Meditate"}, {ID: 2, Name: "Read"}, {ID: 3, Name: "Run"}}
+	weekStart := startOfWeek(time.Now())
+	m := Model{styles: newStyles(lipgloss.DefaultRenderer()), allHabits: habits, habits: habits, weekStart: weekStart, search: newSearchInput()}
+
+	cellText := func(view string, z zone) string {
+		lines := strings.Split(view, "\n")
+		if z.y >= len(lines) {
+			return ""
+		}
+		runes := []rune(lines[z.y])
+		if z.x+z.w > len(runes) {
+			return ""
+		}
+		return string(runes[z.x : z.x+z.w])
+	}
+
+	view, l := renderMain(m)
+	if len(l.zones) != len(habits) {
+		t.Fatalf("main zones = %d, want %d", len(l.zones), len(habits))
+	}
+	for _, z := range l.zones {
+		if got := cellText(view, z); !strings.Contains(got, habits[z.row].Name) {
+			t.Fatalf("row %d zone covers %q", z.row, got)
+		}
+	}
+
+	// A click selects the row under the pointer, accounting for scrolling.
+	z := l.zones[2]
+	m.height = z.y
+	m.scrollOffset = 2
+	next, _ := m.Update(tea.MouseMsg{X: z.x, Y: z.y - 2, Button: tea.MouseButtonLeft, Action: tea.MouseActionPress})
+	if got := next.(Model).cursor; got != 2 {
+		t.Fatalf("cursor after click = %d, want 2", got)
+	}
+
+	m.screen = screenCalendar
+	view, l = renderCalendar(m)
+	days := 0
+	for _, z := range l.zones {
+		if z.kind != zoneDay {
+			continue
+		}
+		days++
+		if z.row == 1 && z.col == 3 {
+			if got := strings.TrimSpace(cellText(view, z)); got != "·" && got != "-" {
+				t.Fatalf("day zone covers %q", got)
+			}
+		}
+	}
+	if days != 7*len(habits) {
+		t.Fatalf("day zones = %d, want %d", days, 7*len(habits))
+	}
+
+	m.screen = screenStats
+	m.stats = &statsSnapshot{}
+	view, l = renderStats(m)
+	for _, z := range l.zones {
+		if got := strings.TrimSpace(cellText(view, z)); got != statsTabNames[z.col] {
+			t.Fatalf("tab %d zone covers %q", z.col, got)
+		}
+	}
+}
+
+func TestThemePickerPreviewsAndReverts(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	themeConfig = &theme.Config{Theme: theme.AllThemes[0].Name}
+	prev := currentTheme
+	defer func() {
+		themeConfig = nil
+		currentTheme = prev
+		applyThemeColors()
+	}()
+	lg := lipgloss.DefaultRenderer()
+	m := useTheme(Model{lg: lg}, theme.AllThemes[0])
+
+	m = openThemePicker(m)
+	down := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")}
+	next, _ := m.Update(down)
+	m = next.(Model)
+	if currentTheme.Name != theme.AllThemes[1].Name || primary != lipgloss.Color(theme.AllThemes[1].Base.Primary) {
+		t.Fatalf("preview theme = %q, want %q", currentTheme.Name, theme.AllThemes[1].Name)
+	}
+	if !strings.Contains(m.View(), "Preview") {
+		t.Fatal("picker does not show the preview")
+	}
+
+	next, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = next.(Model)
+	if m.screen != screenMain || currentTheme.Name != theme.AllThemes[0].Name || themeConfig.Theme != theme.AllThemes[0].Name {
+		t.Fatalf("esc left theme %q (config %q) on screen %d", currentTheme.Name, themeConfig.Theme, m.screen)
+	}
+
+	m = openThemePicker(m)
+	next, _ = m.Update(down)
+	next, _ = next.(Model).Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = next.(Model)
+	if m.screen != screenMain || themeConfig.Theme != theme.AllThemes[1].Name {
+		t.Fatalf("enter saved %q, want %q", themeConfig.Theme, theme.AllThemes[1].Name)
+	}
+	saved, err := theme.LoadConfig()
+	if err != nil || saved.Theme != theme.AllThemes[1].Name {
+		t.Fatalf("persisted theme = %v, %v", saved, err)
+	}
+}
+
+func TestCustomHabitColors(t *testing.T) {
+	prev := currentTheme
+	defer func() {
+		currentTheme = prev
+		applyThemeColors()
+	}()
+	m := useTheme(Model{lg: lipgloss.DefaultRenderer()}, theme.Nord)
+	if got := getHabitColor("blue"); got != lipgloss.Color(theme.Nord.Base.Blue) {
+		t.Fatalf("named color = %q, want the theme's blue", got)
+	}
+	if got := getHabitColor("#1E90FF"); got != "#1e90ff" {
+		t.Fatalf("hex color = %q, want #1e90ff", got)
+	}
+	m = useTheme(m, theme.Dracula)
+	if got := getHabitColor("blue"); got != lipgloss.Color(theme.Dracula.Base.Blue) {
+		t.Fatalf("named color after theme switch = %q", got)
+	}
+	if getHabitColor("#1e90ff") != "#1e90ff" || getHabitColor("208") != "208" {
+		t.Fatal("custom colors should not follow the theme")
+	}
+	if getHabitColor("bogus") != red {
+		t.Fatal("invalid colors should fall back to red")
+	}
+
+	fields := habitFormFieldsFromHabit(models.Habit{Color: "#1e90ff"})
+	if fields.Color != customColor || fields.CustomColor != "#1e90ff" {
+		t.Fatalf("form fields = %q / %q, want custom #1e90ff", fields.Color, fields.CustomColor)
+	}
+	fields.CustomColor = "#ABC"
+	if got, err := formColor(fields); err != nil || got != "#aabbcc" {
+		t.Fatalf("formColor = %q, %v", got, err)
+	}
+	fields.Color = "pink"
+	if got, _ := formColor(fields); got != "pink" {
+		t.Fatalf("formColor = %q, want pink", got)
+	}
+	fields.Color, fields.CustomColor = customColor, "nope"
+	if _, err := formColor(fields); err == nil {
+		t.Fatal("expected an error for an invalid custom color")
+	}
+}
+
+func TestHabitIcons(t *testing.T) {
+	fields := habitFormFieldsFromHabit(models.Habit{Icon: "📚"})
+	if fields.Icon != "📚" || fields.CustomIcon != "" {
+		t.Fatalf("catalog icon = %q / %q, want the select to keep it", fields.Icon, fields.CustomIcon)
+	}
+	fields = habitFormFieldsFromHabit(models.Habit{Icon: "🦄"})
+	if fields.Icon != customIcon || fields.CustomIcon != "🦄" {
+		t.Fatalf("free-form icon = %q / %q, want custom 🦄", fields.Icon, fields.CustomIcon)
+	}
+	if got, err := formIcon(fields); err != nil || got != "🦄" {
+		t.Fatalf("formIcon = %q, %v", got, err)
+	}
+	fields.CustomIcon = "🦄🦄"
+	if _, err := formIcon(fields); err == nil {
+		t.Fatal("expected an error for an icon wider than two columns")
+	}
+
+	// Wide and narrow icons must not shift the calendar's day columns.
+	habits := []models.Habit{
+		{ID: 1, Name: "Morning run routine", Icon: "🏃"},
+		{ID: 2, Name: "Read", Icon: ""},
+		{ID: 3, Name: "Stretch"},
+	}
+	m := Model{styles: newStyles(lipgloss.DefaultRenderer()), allHabits: habits, habits: habits, weekStart: startOfWeek(time.Now()), search: newSearchInput(), screen: screenCalendar}
+	view, l := renderCalendar(m)
+	want := -1
+	for _, z := range l.zones {
+		if z.kind != zoneDay || z.col != 0 {
+			continue
+		}
+		if want == -1 {
+			want = z.x
+		} else if z.x != want {
+			t.Fatalf("row %d starts its days at column %d, want %d", z.row, z.x, want)
+		}
+	}
+	if !strings.Contains(view, "🏃 Morning…") {
+		t.Fatalf("long names should be truncated by display width:\n%s", view)
+	}
+}
+
+func TestIncrementAndDecrementCheckIns(t *testing.T) {
+	store, err := storage.OpenSQLiteAt(filepath.Join(t.TempDir(), "habit.db"), nil)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	defer store.Close()
+	ctx := context.Background()
+	habit, err := store.CreateHabit(ctx, &models.Habit{Name: "Water", Goal: 2, StartDate: time.Now().AddDate(0, 0, -1).Format(time.RFC3339)})
+	if err != nil {
+		t.Fatalf("create habit: %v", err)
+	}
+	habits := []models.Habit{*habit}
+	m := Model{styles: newStyles(lipgloss.DefaultRenderer()), store: store, allHabits: habits, habits: habits, search: newSearchInput()}
+
+	press := func(r string) {
+		t.Helper()
+		next, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(r)})
+		m = next.(Model)
+	}
+	count := func() int { return getCompletionsForHabitAndDate(m.completions, habit.ID, today()) }
+
+	for i := 0; i < 3; i++ {
+		press("+")
+	}
+	if count() != 3 {
+		t.Fatalf("check-ins after three + = %d, want 3 (past the goal)", count())
+	}
+	stored, _ := store.GetCompletionsByHabitIDAndDate(ctx, habit.ID, today())
+	latest := stored[0]
+	for _, c := range stored {
+		if completedAfter(c, latest) {
+			latest = c
+		}
+	}
+	press("-")
+	if count() != 2 {
+		t.Fatalf("check-ins after - = %d, want 2", count())
+	}
+	for _, c := range m.completions {
+		if c.ID == latest.ID {
+			t.Fatal("- should remove the most recent check-in")
+		}
+	}
+	press("-")
+	press("-")
+	press("-")
+	if count() != 0 || !strings.HasPrefix(m.statusMsg, "Nothing to remove") {
+		t.Fatalf("check-ins = %d, status %q", count(), m.statusMsg)
+	}
+
+	// enter still fills one check-in at a time and clears a met goal.
+	next, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = next.(Model)
+	press("+")
+	next, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = next.(Model)
+	if count() != 0 {
+		t.Fatalf("enter on a met goal left %d check-ins", count())
+	}
+}
+
+func TestDayLogEditsCheckIns(t *testing.T) {
+	store, err := storage.OpenSQLiteAt(filepath.Join(t.TempDir(), "habit.db"), nil)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	defer store.Close()
+	ctx := context.Background()
+	habit, err := store.CreateHabit(ctx, &models.Habit{Name: "Water", Goal: 3, StartDate: time.Now().AddDate(0, 0, -1).Format(time.RFC3339)})
+	if err != nil {
+		t.Fatalf("create habit: %v", err)
+	}
+	day := today()
+	for _, hour := range []int{8, 12} {
+		at := time.Date(day.Year(), day.Month(), day.Day(), hour, 0, 0, 0, time.Local)
+		if _, err := store.CreateCompletion(ctx, &models.Completion{HabitID: habit.ID, CompletedAt: at.Format(time.RFC3339)}); err != nil {
+			t.Fatalf("create completion: %v", err)
+		}
+	}
+	habits := []models.Habit{*habit}
+	m := Model{styles: newStyles(lipgloss.DefaultRenderer()), store: store, allHabits: habits, habits: habits, search: newSearchInput(), weekStart: startOfWeek(day)}
+
+	press := func(r string) {
+		t.Helper()
+		next, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(r)})
+		m = next.(Model)
+	}
+	clocks := func() []string {
+		var got []string
+		for _, c := range m.dayLog.entries {
+			clock, _, _ := formatDayLogEntry(m, c)
+			got = append(got, clock)
+		}
+		return got
+	}
+
+	press("d")
+	if m.screen != screenDayLog || m.dayLog.cursor != 1 {
+		t.Fatalf("screen = %v, cursor = %d; want the day log on the latest check-in", m.screen, m.dayLog.cursor)
+	}
+	if got := clocks(); strings.Join(got, " ") != "08:00 12:00" {
+		t.Fatalf("entries = %v", got)
+	}
+
+	press("e")
+	if m.screen != screenDayLogEntry || m.dayLogFields.Time != "12:00" {
+		t.Fatalf("edit form: screen %v, fields %+v", m.screen, m.dayLogFields)
+	}
+	m.dayLogFields.Time = "07:15"
+	m = saveDayLogEntry(m)
+	if got := clocks(); strings.Join(got, " ") != "07:15 08:00" || m.dayLog.cursor != 0 {
+		t.Fatalf("after edit: entries %v, cursor %d", got, m.dayLog.cursor)
+	}
+
+	press("a")
+	m.dayLogFields.Time = "21:30"
+	m = saveDayLogEntry(m)
+	if got := clocks(); strings.Join(got, " ") != "07:15 08:00 21:30" {
+		t.Fatalf("after add: entries %v", got)
+	}
+	if n := getCompletionsForHabitAndDate(m.completions, habit.ID, day); n != 3 {
+		t.Fatalf("today's check-ins = %d, want 3", n)
+	}
+
+	m.dayLog.cursor = 1
+	press("x")
+	press("y")
+	if got := clocks(); strings.Join(got, " ") != "07:15 21:30" {
+		t.Fatalf("after delete: entries %v", got)
+	}
+	if n := getCompletionsForHabitAndDate(m.completions, habit.ID, day); n != 2 {
+		t.Fatalf("today's check-ins = %d, want 2", n)
+	}
+	if view := viewDayLog(m); !strings.Contains(view, "21:30") || !strings.Contains(view, "Water") {
+		t.Fatalf("day log view is missing entries:\n%s", view)
+	}
+
+	next, _ := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = next.(Model)
+	if m.screen != screenMain || m.dayLog != nil {
+		t.Fatalf("esc left screen %v", m.screen)
+	}
+}
+
+func TestHabitDetail(t *testing.T) {
+	store, err := storage.OpenSQLiteAt(filepath.Join(t.TempDir(), "habit.db"), nil)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	defer store.Close()
+	ctx := context.Background()
+	day := today()
+	habit, err := store.CreateHabit(ctx, &models.Habit{
+		Name:        "Read",
+		Description: "Before bed, no phone",
+		Kind:        models.HabitKindQuantity,
+		Unit:        "pages",
+		Target:      10,
+		StartDate:   day.AddDate(0, 0, -9).Format(time.RFC3339),
+	})
+	if err != nil {
+		t.Fatalf("create habit: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		at := time.Date(day.Year(), day.Month(), day.Day()-i, 21, 5, 0, 0, time.Local)
+		if _, err := store.CreateCompletion(ctx, &models.Completion{HabitID: habit.ID, CompletedAt: at.Format(time.RFC3339), Value: 12}); err != nil {
+			t.Fatalf("create completion: %v", err)
+		}
+	}
+	habits := []models.Habit{*habit}
+	m := Model{styles: newStyles(lipgloss.DefaultRenderer()), store: store, allHabits: habits, habits: habits, search: newSearchInput(), weekStart: startOfWeek(day)}
+
+	next, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("i")})
+	m = next.(Model)
+	if m.screen != screenHabitDetail || len(m.detail.completions) != 3 {
+		t.Fatalf("screen = %v, detail = %+v", m.screen, m.detail)
+	}
+	if m.detail.best != 3 || len(m.detail.stats) != len(m.detail.periods) || m.detail.stats[len(m.detail.stats)-1].TotalAmount != 36 {
+		t.Fatalf("detail numbers were not computed on load: %+v", m.detail)
+	}
+	view := viewHabitDetail(m)
+	for _, want := range []string{
+		"Before bed, no phone",
+		"Daily, 10 pages a day",
+		"3 days (best 3)",
+		"36 pages",
+		"Last 7 Days",
+		"All Time",
+		day.Format("Mon Jan 2, 2006") + "  21:05  12 pages",
+	} {
+		if !strings.Contains(view, want) {
+			t.Errorf("detail view is missing %q:\n%s", want, view)
+		}
+	}
+
+	next, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = next.(Model)
+	if m.screen != screenMain {
+		t.Fatalf("esc left screen %v", m.screen)
+	}
+
+	m.weekStart = startOfWeek(day).AddDate(0, 0, -14)
+	m = openHabitDetail(m, screenMain)
+	next, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")})
+	m = next.(Model)
+	if m.screen != screenCalendar || !m.weekStart.AddDate(0, 0, m.calendarCol).Equal(day) {
+		t.Fatalf("calendar opened on %v col %d, want today", m.weekStart, m.calendarCol)
+	}
+}
+
+func TestArchivedHabitDetail(t *testing.T) {
+	store, err := storage.OpenSQLiteAt(filepath.Join(t.TempDir(), "habit.db"), nil)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	defer store.Close()
+	ctx := context.Background()
+	day := today()
+	var ids []int64
+	for _, name := range []string{"Run", "Swim"} {
+		habit, err := store.CreateHabit(ctx, &models.Habit{Name: name, StartDate: day.AddDate(0, 0, -5).Format(time.RFC3339)})
+		if err != nil {
+			t.Fatalf("create habit: %v", err)
+		}
+		ids = append(ids, habit.ID)
+	}
+	for i := 1; i <= 2; i++ {
+		at := time.Date(day.Year(), day.Month(), day.Day()-i, 8, 0, 0, 0, time.Local)
+		if _, err := store.CreateCompletion(ctx, &models.Completion{HabitID: ids[1], CompletedAt: at.Format(time.RFC3339)}); err != nil {
+			t.Fatalf("create completion: %v", err)
+		}
+	}
+	for _, id := range ids {
+		if err := store.ArchiveHabit(ctx, id); err != nil {
+			t.Fatalf("archive habit: %v", err)
+		}
+	}
+	m := Model{styles: newStyles(lipgloss.DefaultRenderer()), store: store, search: newSearchInput(), weekStart: startOfWeek(day)}
+	m = openArchive(m)
+	if len(m.archiveSummaries) != 2 || m.archiveSummaries[0].checkIns+m.archiveSummaries[1].checkIns != 2 {
+		t.Fatalf("archive summaries = %+v", m.archiveSummaries)
+	}
+	if view := viewArchive(m); !strings.Contains(view, "2 check-ins  |  best streak 2 days") {
+		t.Errorf("archive view is missing the Swim summary:\n%s", view)
+	}
+
+	m.archiveCursor = 1
+	next, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("i")})
+	m = next.(Model)
+	if m.screen != screenHabitDetail || m.detail.habit.ID != m.archivedHabits[1].ID {
+		t.Fatalf("screen = %v, detail = %+v", m.screen, m.detail)
+	}
+	if view := viewHabitDetail(m); !strings.Contains(view, "Archived") {
+		t.Errorf("detail view does not show the archive date:\n%s", view)
+	}
+	next, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("k")})
+	m = next.(Model)
+	if m.archiveCursor != 0 || m.detail.habit.ID != m.archivedHabits[0].ID {
+		t.Fatalf("k stepped to %d (%s)", m.archiveCursor, m.detail.habit.Name)
+	}
+	next, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("z")})
+	m = next.(Model)
+	if m.screen != screenHabitDetail {
+		t.Fatalf("z on an archived habit left screen %v", m.screen)
+	}
+	next, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = next.(Model)
+	if m.screen != screenArchive {
+		t.Fatalf("esc left screen %v, want the archive", m.screen)
+	}
+}
@@ -0,0 +1,65 @@
+package view
+
+import (
+	"log"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/bShaak/habitui/internal/keymap"
+	"github.com/bShaak/habitui/internal/theme"
+	"github.com/charmbracelet/bubbles/key"
+)
+
+// keys holds the active bindings, defaults overlaid with the config's "keys".
+var keys = keymap.Default()
+
+func initKeys(config *theme.Config) {
+	var overrides map[string][]string
+	if config != nil {
+		overrides = config.Keys
+	}
+	km, err := keymap.New(overrides)
+	if err != nil {
+		log.Printf("Error in key config: %s", err)
+	}
+	keys = km
+}
+
+// helpKey shows the keys of bindings as in help lines, e.g. "j/k".
+func helpKey(bindings ...key.Binding) string {
+	shown := make([]string, len(bindings))
+	for i, b := range bindings {
+		shown[i] = b.Help().Key
+	}
+	return strings.Join(shown, "/")
+}
+
+// helpItem renders "keys: desc" for one help line entry.
+func helpItem(desc string, bindings ...key.Binding) string {
+	return helpKey(bindings...) + ": " + desc
+}
+
+// helpLine joins help entries with the usual separator.
+func helpLine(items ...string) string {
+	return strings.Join(items, "  |  ")
+}
+
+// confirmHelp is the hint shown on delete confirmations.
+func confirmHelp() string {
+	return helpLine(helpItem("confirm", keys.Confirm), helpItem("cancel", keys.Cancel, keys.Back))
+}
+
+// submitKey is Confirm without the keys that would type text (like "y"), so
+// search and form fields can accept it without swallowing letters.
+func submitKey() key.Binding {
+	var submit []string
+	for _, k := range keys.Confirm.Keys() {
+		if utf8.RuneCountInString(k) > 1 {
+			submit = append(submit, k)
+		}
+	}
+	if len(submit) == 0 {
+		submit = []string{"enter"}
+	}
+	return key.NewBinding(key.WithKeys(submit...), key.WithHelp(strings.Join(submit, "/"), "submit"))
+}
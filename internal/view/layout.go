@@ -0,0 +1,155 @@
+package view
+
+import (
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// doubleClickWindow is how soon a second click on the same zone counts as a
+// double click.
+const doubleClickWindow = 400 * time.Millisecond
+
+// wheelLines is how far one wheel notch scrolls.
+const wheelLines = 3
+
+type zoneKind int
+
+const (
+	zoneHabit zoneKind = iota + 1 // a habit row; row is the index in m.habits
+	zoneDay                       // a calendar cell; row is the habit, col the day
+	zoneTab                       // a stats tab; col is the tab
+)
+
+// zone is a clickable rectangle in screen content coordinates, i.e. before
+// the viewport scrolls it.
+type zone struct {
+	kind     zoneKind
+	row, col int
+	x, y     int
+	w, h     int
+}
+
+func (z zone) contains(x, y int) bool {
+	return x >= z.x && x < z.x+z.w && y >= z.y && y < z.y+z.h
+}
+
+// layout collects zones while a screen renders, so hit-testing uses the same
+// positions as the text on screen.
+type layout struct {
+	zones []zone
+}
+
+// mark records a one-line zone of width w starting where the next write to b
+// will land.
+func (l *layout) mark(b *strings.Builder, kind zoneKind, row, col, w int) {
+	s := b.String()
+	y := strings.Count(s, "\n")
+	x := lipgloss.Width(s[strings.LastIndex(s, "\n")+1:])
+	l.zones = append(l.zones, zone{kind: kind, row: row, col: col, x: x, y: y, w: w, h: 1})
+}
+
+// embed adds inner's zones shifted by (dx, dy).
+func (l *layout) embed(inner layout, dx, dy int) {
+	for _, z := range inner.zones {
+		z.x += dx
+		z.y += dy
+		l.zones = append(l.zones, z)
+	}
+}
+
+// embedBox adds zones from content rendered inside ContentBox, which is
+// written after head.
+func (l *layout) embedBox(s *Styles, head string, inner layout) {
+	box := s.ContentBox
+	dx := box.GetMarginLeft() + box.GetBorderLeftSize() + box.GetPaddingLeft()
+	dy := box.GetMarginTop() + box.GetBorderTopSize() + box.GetPaddingTop()
+	l.embed(inner, dx, strings.Count(head, "\n")+dy)
+}
+
+// framed shifts zones by the Base style wrapping every screen.
+func (l layout) framed(s *Styles) layout {
+	var out layout
+	out.embed(l, s.Base.GetPaddingLeft(), s.Base.GetPaddingTop())
+	return out
+}
+
+func (l layout) at(x, y int) (zone, bool) {
+	for _, z := range l.zones {
+		if z.contains(x, y) {
+			return z, true
+		}
+	}
+	return zone{}, false
+}
+
+// screenLayout renders the current screen for its zones.
+func (m Model) screenLayout() layout {
+	if m.showHelp {
+		return layout{}
+	}
+	var l layout
+	switch m.screen {
+	case screenMain:
+		_, l = renderMain(m)
+	case screenCalendar:
+		_, l = renderCalendar(m)
+	case screenStats:
+		_, l = renderStats(m)
+	}
+	return l
+}
+
+type lastClick struct {
+	zone zone
+	at   time.Time
+}
+
+func updateMouse(m Model, msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	switch msg.Button {
+	case tea.MouseButtonWheelUp:
+		m.scrollOffset = clampScroll(m.scrollOffset-wheelLines, 0, maxScroll(m))
+		return m, nil
+	case tea.MouseButtonWheelDown:
+		m.scrollOffset = clampScroll(m.scrollOffset+wheelLines, 0, maxScroll(m))
+		return m, nil
+	case tea.MouseButtonLeft:
+		if msg.Action != tea.MouseActionPress || m.confirmingDelete {
+			return m, nil
+		}
+	default:
+		return m, nil
+	}
+
+	offset := clampScroll(m.scrollOffset, 0, maxScroll(m))
+	z, ok := m.screenLayout().at(msg.X, msg.Y+offset)
+	if !ok {
+		m.lastClick = lastClick{}
+		return m, nil
+	}
+	now := time.Now()
+	double := m.lastClick.zone == z && now.Sub(m.lastClick.at) <= doubleClickWindow
+	m.lastClick = lastClick{zone: z, at: now}
+
+	switch z.kind {
+	case zoneHabit:
+		m.cursor = z.row
+		if double && m.screen == screenMain {
+			m.lastClick = lastClick{}
+			return toggleSelectedHabit(m)
+		}
+	case zoneDay:
+		m.cursor = z.row
+		m.calendarCol = z.col
+		return toggleCalendarCell(m)
+	case zoneTab:
+		if z.col != m.statsTab {
+			m.statsTab = z.col
+			m.statsOffset = 0
+			return refreshStats(m), nil
+		}
+	}
+	return m, nil
+}
@@ -0,0 +1,149 @@
+package view
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bShaak/habitui/internal/models"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+)
+
+// amountEntry is the pending "how much?" prompt for a quantity habit.
+type amountEntry struct {
+	habit models.Habit
+	day   time.Time
+	from  screen
+	Value string
+}
+
+// parseAmount accepts non-negative decimals, with either "." or "," as separator.
+func parseAmount(str string) (float64, error) {
+	str = strings.ReplaceAll(strings.TrimSpace(str), ",", ".")
+	if str == "" {
+		return 0, errors.New("enter an amount")
+	}
+	v, err := strconv.ParseFloat(str, 64)
+	if err != nil {
+		return 0, errors.New("amount must be a number")
+	}
+	if v < 0 {
+		return 0, errors.New("amount must not be negative")
+	}
+	return v, nil
+}
+
+func buildAmountForm(entry *amountEntry, logged float64) *huh.Form {
+	unit := entry.habit.Unit
+	if unit == "" {
+		unit = "amount"
+	}
+	return huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Title(fmt.Sprintf("Add %s — %s", unit, entry.day.Format("Mon Jan 2"))).
+				Description(fmt.Sprintf("Logged %s so far. Enter 0 to clear the day.", entry.habit.FormatProgress(logged))).
+				Key("amount").
+				Value(&entry.Value).
+				Validate(func(str string) error {
+					_, err := parseAmount(str)
+					return err
+				}),
+		),
+	).WithWidth(60).WithTheme(formTheme()).WithKeyMap(formKeyMap())
+}
+
+// completionsFor returns the completion list backing screen s.
+func (m Model) completionsFor(s screen) []models.Completion {
+	if s == screenCalendar {
+		return m.weekCompletions
+	}
+	return m.completions
+}
+
+func openAmountEntry(m Model, habit models.Habit, day time.Time, from screen) (Model, tea.Cmd) {
+	entry := &amountEntry{habit: habit, day: day, from: from}
+	logged := getAmountForHabitAndDate(m.completionsFor(from), habit, day)
+	m.statusMsg = ""
+	m.amountEntry = entry
+	m.form = buildAmountForm(entry, logged)
+	applyFormSize(m.form, m.width, m.height)
+	m.scrollOffset = 0
+	m.screen = screenLogAmount
+	return m, m.form.Init()
+}
+
+func closeAmountEntry(m Model) Model {
+	from := screenMain
+	if m.amountEntry != nil {
+		from = m.amountEntry.from
+	}
+	m.form = nil
+	m.amountEntry = nil
+	m.screen = from
+	return m
+}
+
+func updateLogAmount(m Model, msg tea.Msg) (tea.Model, tea.Cmd) {
+	if _, ok := msg.(tea.WindowSizeMsg); ok {
+		applyFormSize(m.form, m.width, m.height)
+		return m, nil
+	}
+	if m.amountEntry == nil {
+		return closeAmountEntry(m), nil
+	}
+
+	var cmds []tea.Cmd
+	form, cmd := m.form.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.form = f
+		cmds = append(cmds, cmd)
+	}
+	if m.form.State != huh.StateCompleted {
+		return m, tea.Batch(cmds...)
+	}
+
+	entry := m.amountEntry
+	value, err := parseAmount(entry.Value)
+	if err != nil {
+		m.setError("Could not log amount: " + err.Error())
+		return closeAmountEntry(m), nil
+	}
+	updated, err := m.logDayAmount(entry.habit, entry.day, value, m.completionsFor(entry.from))
+	if err != nil {
+		log.Printf("Error logging amount: %s", err)
+		m.setError("Could not log amount")
+		return closeAmountEntry(m), nil
+	}
+	if entry.from == screenCalendar {
+		m.weekCompletions = updated
+	} else {
+		m.completions = updated
+	}
+	m = refreshStreakCompletions(m)
+	m = arrangeHabits(m)
+	return closeAmountEntry(m), nil
+}
+
+func viewLogAmount(m Model) string {
+	s := m.styles
+	var b strings.Builder
+	b.WriteString(m.renderTitle())
+	b.WriteString("\n")
+	title := "Log Amount"
+	if m.amountEntry != nil {
+		title = "Log " + m.amountEntry.habit.Label()
+	}
+	b.WriteString(m.appBoundaryView(title))
+	b.WriteString("\n\n")
+	var content strings.Builder
+	content.WriteString(m.form.View())
+	content.WriteString("\n\n")
+	content.WriteString(s.Help.Render(helpLine(helpItem("save", submitKey()), helpItem("cancel", keys.Back))))
+	b.WriteString(s.ContentBox.Render(content.String()))
+	return s.Base.Render(b.String())
+}
@@ -5,8 +5,8 @@ import (
 	"fmt"
 	"log"
 	"strings"
-	"time"
 
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
@@ -15,21 +15,24 @@ func updateMain(m Model, msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		if m.confirmingDelete {
-			switch msg.String() {
-			case "y", "enter":
+			switch {
+			case key.Matches(msg, keys.Confirm):
 				return deleteSelectedHabit(m), nil
-			case "n", "x":
+			case key.Matches(msg, keys.Cancel, keys.Delete):
 				m.confirmingDelete = false
 				return m, nil
 			default:
 				return m, nil
 			}
 		}
+		if m.searching {
+			return updateSearch(m, msg)
+		}
 
-		switch msg.String() {
-		case "q":
+		switch {
+		case key.Matches(msg, keys.Quit):
 			return m, tea.Quit
-		case "a":
+		case key.Matches(msg, keys.Add):
 			m.statusMsg = ""
 			form, fields := createHabitForm()
 			m.form = form
@@ -38,89 +41,124 @@ func updateMain(m Model, msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.scrollOffset = 0
 			m.screen = screenCreateHabit
 			return m, m.form.Init()
-		case "c":
-			m.statusMsg = ""
-			habits, err := m.store.ListHabits(context.Background())
-			if err != nil {
-				log.Printf("Error fetching habits: %s", err)
-			}
-			m.habits = habits
-			weekEnd := m.weekStart.AddDate(0, 0, 6)
-			completions, err := m.store.GetCompletionsByDateRange(context.Background(), m.weekStart, weekEnd)
-			if err != nil {
-				log.Printf("Error fetching week completions: %s", err)
-			}
-			m.weekCompletions = completions
-			if m.cursor >= len(m.habits) {
-				m.cursor = 0
-			}
-			m.calendarCol = 0
-			m.scrollOffset = 0
-			m.screen = screenCalendar
-			return m, nil
-		case "s":
+		case key.Matches(msg, keys.Calendar):
+			return openCalendar(m), nil
+		case key.Matches(msg, keys.Stats):
 			m.statusMsg = ""
-			habits, err := m.store.ListHabits(context.Background())
-			if err != nil {
-				log.Printf("Error fetching habits: %s", err)
-			}
-			m.habits = habits
-			if m.cursor >= len(m.habits) {
-				m.cursor = 0
-			}
-			statsCompletions, err := m.store.ListCompletions(context.Background())
-			if err != nil {
-				log.Printf("Error fetching completions for stats: %s", err)
-			}
-			m.statsCompletions = statsCompletions
+			m = reloadHabits(m)
 			m.statsTab = 0
+			m.statsOffset = 0
 			m.scrollOffset = 0
 			m.screen = screenStats
-			return m, nil
-		case "e":
-			m.statusMsg = ""
-			if len(m.habits) == 0 {
-				return m, nil
-			}
-			form, fields := editHabitForm(m.habits[m.cursor])
-			m.form = form
-			m.formFields = fields
-			applyFormSize(m.form, m.width, m.height)
-			m.scrollOffset = 0
-			m.screen = screenEditHabit
-			return m, m.form.Init()
-		case "k":
+			return refreshStats(m), nil
+		case key.Matches(msg, keys.Edit):
+			return openEditHabit(m)
+		case key.Matches(msg, keys.Up):
 			if m.cursor > 0 {
 				m.cursor--
 			}
-		case "j":
+		case key.Matches(msg, keys.Down):
 			if m.cursor < len(m.habits)-1 {
 				m.cursor++
 			}
-		case "x":
+		case key.Matches(msg, keys.MoveUp):
+			return moveSelectedHabit(m, -1), nil
+		case key.Matches(msg, keys.MoveDown):
+			return moveSelectedHabit(m, 1), nil
+		case key.Matches(msg, keys.Sort):
+			return cycleSortMode(m), nil
+		case key.Matches(msg, keys.TagFilter):
+			return cycleTagFilter(m), nil
+		case key.Matches(msg, keys.Search):
+			return startSearch(m)
+		case key.Matches(msg, keys.Delete):
 			if len(m.habits) > 0 {
 				m.confirmingDelete = true
 			}
-		case "t":
-			return cycleTheme(m), nil
-		case "enter":
-			if len(m.habits) == 0 {
-				return m, nil
-			}
-			selected := m.habits[m.cursor]
-			m.statusMsg = ""
-			updated, err := m.toggleDayCompletion(selected, time.Now(), m.completions)
-			if err != nil {
-				log.Printf("Error toggling completion: %s", err)
-				return m, nil
+		case key.Matches(msg, keys.Archive):
+			return archiveSelectedHabit(m), nil
+		case key.Matches(msg, keys.Archived):
+			return openArchive(m), nil
+		case key.Matches(msg, keys.Vacations):
+			return openVacations(m), nil
+		case key.Matches(msg, keys.DayLog):
+			var habitID int64
+			if len(m.habits) > 0 {
+				habitID = m.habits[m.cursor].ID
 			}
-			m.completions = updated
-			m = refreshStreakCompletions(m)
+			return openDayLog(m, today(), screenMain, habitID), nil
+		case key.Matches(msg, keys.Details):
+			return openHabitDetail(m, screenMain), nil
+		case key.Matches(msg, keys.Heatmap):
+			return openHeatmap(m, 0), nil
+		case key.Matches(msg, keys.Theme):
+			return openThemePicker(m), nil
+		case key.Matches(msg, keys.Toggle):
+			return toggleSelectedHabit(m)
+		case key.Matches(msg, keys.Increment):
+			return stepSelectedHabit(m, 1)
+		case key.Matches(msg, keys.Decrement):
+			return stepSelectedHabit(m, -1)
 		}
 	}
 	return m, nil
 }
 
+// openCalendar shows the week calendar at m.weekStart.
+func openCalendar(m Model) Model {
+	m.statusMsg = ""
+	m = reloadHabits(m)
+	weekEnd := m.weekStart.AddDate(0, 0, 6)
+	completions, err := m.store.GetCompletionsByDateRange(context.Background(), m.weekStart, weekEnd)
+	if err != nil {
+		log.Printf("Error fetching week completions: %s", err)
+	}
+	m.weekCompletions = completions
+	if m.cursor >= len(m.habits) {
+		m.cursor = 0
+	}
+	m.calendarCol = 0
+	m.scrollOffset = 0
+	m.screen = screenCalendar
+	return m
+}
+
+// openEditHabit opens the edit form for the selected habit.
+func openEditHabit(m Model) (Model, tea.Cmd) {
+	m.statusMsg = ""
+	if len(m.habits) == 0 {
+		return m, nil
+	}
+	form, fields := editHabitForm(m.habits[m.cursor])
+	m.form = form
+	m.formFields = fields
+	applyFormSize(m.form, m.width, m.height)
+	m.scrollOffset = 0
+	m.screen = screenEditHabit
+	return m, m.form.Init()
+}
+
+// toggleSelectedHabit checks the selected habit in or out for today, or asks
+// for an amount when it is a quantity habit.
+func toggleSelectedHabit(m Model) (tea.Model, tea.Cmd) {
+	if len(m.habits) == 0 {
+		return m, nil
+	}
+	selected := m.habits[m.cursor]
+	m.statusMsg = ""
+	if selected.IsQuantity() {
+		return openAmountEntry(m, selected, today(), screenMain)
+	}
+	updated, err := m.toggleDayCompletion(selected, today(), m.completions)
+	if err != nil {
+		log.Printf("Error toggling completion: %s", err)
+		return m, nil
+	}
+	m.completions = updated
+	m = refreshStreakCompletions(m)
+	return arrangeHabits(m), nil
+}
+
 func deleteSelectedHabit(m Model) Model {
 	if len(m.habits) == 0 {
 		m.confirmingDelete = false
@@ -132,40 +170,82 @@ func deleteSelectedHabit(m Model) Model {
 		m.confirmingDelete = false
 		return m
 	}
-	if m.cursor == len(m.habits)-1 {
-		m.habits = m.habits[:m.cursor]
+	m = removeHabitAt(m, m.cursor)
+	m.confirmingDelete = false
+	return m
+}
+
+// removeHabitAt drops the habit at idx from the list and keeps the cursor in range.
+func removeHabitAt(m Model, idx int) Model {
+	id := m.habits[idx].ID
+	all := m.allHabits[:0:0]
+	for _, h := range m.allHabits {
+		if h.ID != id {
+			all = append(all, h)
+		}
+	}
+	m.allHabits = all
+	if idx == len(m.habits)-1 {
+		m.habits = m.habits[:idx]
 	} else {
-		m.habits = append(m.habits[:m.cursor], m.habits[m.cursor+1:]...)
+		m.habits = append(m.habits[:idx], m.habits[idx+1:]...)
 	}
 	if m.cursor > 0 && m.cursor >= len(m.habits) {
 		m.cursor--
 	}
-	m.confirmingDelete = false
-	return m
+	return arrangeHabits(m)
+}
+
+// setStatus shows msg as a confirmation.
+func (m *Model) setStatus(msg string) {
+	m.statusMsg = msg
+	m.statusInfo = true
 }
 
-func statusStyle(msg string) lipgloss.Style {
-	if strings.HasPrefix(msg, "Theme:") {
+// setError shows msg as an error.
+func (m *Model) setError(msg string) {
+	m.statusMsg = msg
+	m.statusInfo = false
+}
+
+// statusStyle colors confirmations with the accent and errors in red.
+func statusStyle(info bool) lipgloss.Style {
+	if info {
 		return lipgloss.NewStyle().Foreground(primary)
 	}
 	return lipgloss.NewStyle().Foreground(red)
 }
 
 func viewMain(m Model) string {
+	v, _ := renderMain(m)
+	return v
+}
+
+// renderMain draws the habit list and records a zone for each row.
+func renderMain(m Model) (string, layout) {
 	s := m.styles
+	var l layout
 	var b strings.Builder
 	b.WriteString(m.renderTitle())
 	b.WriteString("\n")
-	header := m.appBoundaryView("Today's Habits")
+	header := m.appBoundaryView(m.withTagFilter("Today's Habits"))
 	b.WriteString(header)
 	b.WriteString("\n\n")
 	var content strings.Builder
-	if len(m.habits) == 0 {
-		content.WriteString(s.Help.Render("No habits created yet.\n\nPress 'a' to create a new one.  |  t: Theme"))
+	if m.searchActive() {
+		content.WriteString(m.viewSearchLine())
+		content.WriteString("\n\n")
+	}
+	if len(m.habits) == 0 && m.searchActive() {
+		content.WriteString(s.Help.Render("No habits match."))
+	} else if len(m.habits) == 0 {
+		content.WriteString(s.Help.Render(fmt.Sprintf("No habits created yet.\n\nPress '%s' to create a new one.", helpKey(keys.Add))))
+		content.WriteString("\n\n")
 		if m.statusMsg != "" {
-			content.WriteString("\n\n")
-			content.WriteString(statusStyle(m.statusMsg).Render(m.statusMsg))
+			content.WriteString(statusStyle(m.statusInfo).Render(m.statusMsg))
+			content.WriteString("\n")
 		}
+		content.WriteString(m.shortHelp())
 	} else {
 		for i, h := range m.habits {
 			cursor := " "
@@ -173,22 +253,30 @@ func viewMain(m Model) string {
 				cursor = ">"
 			}
 			habitColor := getHabitColor(h.Color)
-			scheduledToday := isScheduledOnDay(h.Frequency, getDayName(time.Now()))
+			scheduledToday := isDueOn(h, m.streakCompletions, m.pauses, today())
 			completed := ""
 			if isCompleted(m.completions, h) {
 				completed = "✓"
+				if h.IsQuantity() {
+					completed = fmt.Sprintf("✓ (%s)", h.FormatProgress(todayAmount(m.completions, h)))
+				}
+			} else if glyph, label := pauseGlyph(m.pauses, h.ID, today()); glyph != "" {
+				completed = glyph + " " + label
 			} else if scheduledToday {
-				completionCount := todayCompletionCount(m.completions, h.ID)
-				completed = fmt.Sprintf("✗ (%d/%d)", completionCount, effectiveGoal(h.Goal))
+				completed = fmt.Sprintf("✗ (%s)", h.FormatProgress(todayAmount(m.completions, h)))
 			}
-			name := formatHabitLabel(h)
+			name := h.Label()
 			if name == "" || strings.TrimSpace(h.Name) == "" {
 				name = "Unnamed"
 				if h.Icon != "" {
 					name = h.Icon + " Unnamed"
 				}
 			}
-			currentStreak, _ := getHabitStreak(h, m.streakCompletions, time.Now())
+			if h.Recurrence.IsWeeklyQuota() {
+				met, quota := weeklyQuotaProgress(h, m.streakCompletions, m.pauses, today())
+				completed = strings.TrimSpace(fmt.Sprintf("%s  %d/%d this week", completed, met, quota))
+			}
+			currentStreak, _ := getHabitStreak(h, m.streakCompletions, m.pauses, today())
 			streakText := ""
 			if currentStreak >= 3 {
 				streakText = fmt.Sprintf(" 🔥 %d", currentStreak)
@@ -196,29 +284,38 @@ func viewMain(m Model) string {
 			nameStyle := lipgloss.NewStyle().Foreground(habitColor)
 			completedStyle := lipgloss.NewStyle().Foreground(habitColor)
 			streakStyle := lipgloss.NewStyle().Foreground(orange)
-			content.WriteString(fmt.Sprintf("%s %s %s%s\n",
+			tagText := ""
+			if len(h.Tags) > 0 && m.tagFilter == "" {
+				tagText = "  #" + strings.Join(h.Tags, " #")
+			}
+			row := fmt.Sprintf("%s %s %s%s%s",
 				cursor,
 				nameStyle.Render(name),
 				completedStyle.Render(completed),
 				streakStyle.Render(streakText),
-			))
+				s.Help.Render(tagText),
+			)
+			l.mark(&content, zoneHabit, i, 0, lipgloss.Width(row))
+			content.WriteString(row)
+			content.WriteString("\n")
 		}
 		content.WriteString("\n")
 		if m.confirmingDelete {
-			habitName := formatHabitLabel(m.habits[m.cursor])
+			habitName := m.habits[m.cursor].Label()
 			confirm := lipgloss.NewStyle().Foreground(red).Bold(true).Render(
-				fmt.Sprintf("Delete %q?  y: confirm  |  n/esc: cancel", habitName),
+				fmt.Sprintf("Delete %q?  %s", habitName, confirmHelp()),
 			)
 			content.WriteString(confirm)
 		} else {
 			if m.statusMsg != "" {
-				content.WriteString(statusStyle(m.statusMsg).Render(m.statusMsg))
+				content.WriteString(statusStyle(m.statusInfo).Render(m.statusMsg))
 				content.WriteString("\n")
 			}
-			help := s.Help.Render("a: Add  |  c: Calendar  |  s: Stats  |  e: Edit  |  x: Delete  |  t: Theme  |  enter: Toggle  |  q: Quit")
-			content.WriteString(help)
+			content.WriteString(m.shortHelp())
 		}
 	}
+	var frame layout
+	frame.embedBox(s, b.String(), l)
 	b.WriteString(s.ContentBox.Render(content.String()))
-	return s.Base.Render(b.String())
+	return s.Base.Render(b.String()), frame.framed(s)
 }
@@ -3,7 +3,6 @@ package view
 import (
 	"context"
 	"log"
-	"strconv"
 	"strings"
 	"time"
 
@@ -42,33 +41,43 @@ func updateCreateHabit(m Model, msg tea.Msg) (tea.Model, tea.Cmd) {
 	if name == "" {
 		name = "Unnamed Habit"
 	}
-	goalInt, err := strconv.Atoi(m.formFields.GoalString)
-	if err != nil || goalInt < 1 {
-		log.Printf("Error converting goal to int: %v", err)
-		m.statusMsg = "Could not create habit: goal must be a number ≥ 1"
+	color, err := formColor(m.formFields)
+	if err != nil {
+		m.setError("Could not create habit: " + err.Error())
 		return returnToMain(m), nil
 	}
-	color := m.formFields.Color
-	if color == "" {
-		color = "red"
+	icon, err := formIcon(m.formFields)
+	if err != nil {
+		m.setError("Could not create habit: " + err.Error())
+		return returnToMain(m), nil
 	}
 	habit := models.Habit{
 		Name:        name,
 		Description: m.formFields.Description,
-		Frequency:   formFrequency(m.formFields),
-		Goal:        goalInt,
 		Color:       color,
-		Icon:        m.formFields.Icon,
+		Icon:        icon,
+		Tags:        models.ParseTags(m.formFields.Tags),
 		StartDate:   time.Now().Format(time.RFC3339),
 	}
+	if err := applyFormGoal(m.formFields, &habit); err != nil {
+		log.Printf("Error reading habit goal: %v", err)
+		m.setError("Could not create habit: " + err.Error())
+		return returnToMain(m), nil
+	}
+	if err := applyFormSchedule(m.formFields, &habit); err != nil {
+		log.Printf("Error reading habit schedule: %v", err)
+		m.setError("Could not create habit: " + err.Error())
+		return returnToMain(m), nil
+	}
 
 	h, err := m.store.CreateHabit(context.Background(), &habit)
 	if err != nil {
 		log.Printf("Error creating habit: %s", err)
-		m.statusMsg = "Could not create habit"
+		m.setError("Could not create habit")
 		return returnToMain(m), nil
 	}
-	m.habits = append(m.habits, *h)
+	m.allHabits = append(m.allHabits, *h)
+	m = arrangeHabits(m)
 	m.statusMsg = ""
 	return returnToMain(m), nil
 }
@@ -84,7 +93,7 @@ func viewCreateHabit(m Model) string {
 	var content strings.Builder
 	content.WriteString(m.form.View())
 	content.WriteString("\n\n")
-	help := s.Help.Render("tab: next  |  shift+tab: back  |  esc: cancel")
+	help := s.Help.Render(helpLine("tab: next", "shift+tab: back", helpItem("cancel", keys.Back)))
 	content.WriteString(help)
 	b.WriteString(s.ContentBox.Render(content.String()))
 	return s.Base.Render(b.String())
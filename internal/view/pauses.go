@@ -0,0 +1,59 @@
+package view
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/bShaak/habitui/internal/models"
+	"github.com/bShaak/habitui/internal/storage"
+)
+
+const (
+	skipGlyph     = "⊘"
+	vacationGlyph = "≈"
+)
+
+func loadPauses(m Model) Model {
+	pauses, err := storage.LoadPauses(context.Background(), m.store)
+	if err != nil {
+		log.Printf("Error fetching skips and vacations: %s", err)
+		return m
+	}
+	m.pauses = pauses
+	return m
+}
+
+// toggleSkip skips day for habitID (0 for every habit), or un-skips it if it
+// already was.
+func toggleSkip(m Model, habitID int64, day time.Time) Model {
+	ctx := context.Background()
+	key := models.DayKey(day)
+	skipped := m.pauses.SkippedForHabit(habitID, day)
+	var err error
+	if skipped {
+		err = m.store.DeleteSkip(ctx, habitID, key)
+	} else {
+		_, err = m.store.CreateSkip(ctx, &models.Skip{HabitID: habitID, Day: key})
+	}
+	if err != nil {
+		log.Printf("Error updating skip: %s", err)
+		m.setError("Could not update skip")
+		return m
+	}
+	m = loadPauses(m)
+	m.statusMsg = ""
+	return m
+}
+
+// pauseGlyph returns the glyph and label for a paused day, or "" if the habit
+// is due as usual.
+func pauseGlyph(pauses models.Pauses, habitID int64, day time.Time) (string, string) {
+	if _, ok := pauses.VacationOn(day); ok {
+		return vacationGlyph, "vacation"
+	}
+	if pauses.IsSkipped(habitID, day) {
+		return skipGlyph, "skipped"
+	}
+	return "", ""
+}
@@ -0,0 +1,113 @@
+package view
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/bShaak/habitui/internal/models"
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+func newSearchInput() textinput.Model {
+	ti := textinput.New()
+	ti.Prompt = "/"
+	ti.Placeholder = "filter habits"
+	ti.CharLimit = 64
+	// Blink messages aren't routed to the input, so keep the cursor solid.
+	ti.Cursor.SetMode(cursor.CursorStatic)
+	return ti
+}
+
+// fuzzyMatch reports whether the runes of query appear in target in order,
+// ignoring case (so "mdt" matches "Meditate").
+func fuzzyMatch(query, target string) bool {
+	q := []rune(strings.ToLower(query))
+	if len(q) == 0 {
+		return true
+	}
+	i := 0
+	for _, r := range strings.ToLower(target) {
+		if r == q[i] {
+			i++
+			if i == len(q) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matchesSearch reports whether every word of query fuzzy-matches the habit's
+// name, icon or description.
+func matchesSearch(habit models.Habit, query string) bool {
+	for _, word := range strings.FieldsFunc(query, unicode.IsSpace) {
+		if !fuzzyMatch(word, habit.Name) && !fuzzyMatch(word, habit.Icon) && !fuzzyMatch(word, habit.Description) {
+			return false
+		}
+	}
+	return true
+}
+
+func (m Model) searchQuery() string {
+	return strings.TrimSpace(m.search.Value())
+}
+
+func startSearch(m Model) (Model, tea.Cmd) {
+	m.statusMsg = ""
+	m.searching = true
+	m.search.Width = max(10, m.width-12)
+	return m, m.search.Focus()
+}
+
+// clearSearch leaves search mode and shows every habit again.
+func clearSearch(m Model) Model {
+	m.searching = false
+	m.search.Blur()
+	m.search.SetValue("")
+	return arrangeHabits(m)
+}
+
+// updateSearch feeds keys to the search input while it has focus. The submit
+// keys keep the filter and return the keys to the list; esc is handled
+// globally.
+func updateSearch(m Model, msg tea.KeyMsg) (Model, tea.Cmd) {
+	if key.Matches(msg, submitKey()) {
+		m.searching = false
+		m.search.Blur()
+		if m.searchQuery() == "" {
+			m.search.SetValue("")
+		}
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.search, cmd = m.search.Update(msg)
+	return arrangeHabits(m), cmd
+}
+
+// searchActive reports whether the search line should be shown.
+func (m Model) searchActive() bool {
+	return m.searching || m.searchQuery() != ""
+}
+
+// withHabitFilters is withTagFilter plus the search query, for screens that
+// list the filtered habits without showing the search line.
+func (m Model) withHabitFilters(title string) string {
+	title = m.withTagFilter(title)
+	if query := m.searchQuery(); query != "" {
+		title += "  /" + query
+	}
+	return title
+}
+
+// viewSearchLine renders the search input, or the applied query once typing is done.
+func (m Model) viewSearchLine() string {
+	if m.searching {
+		return m.search.View()
+	}
+	return lipgloss.NewStyle().Foreground(primary).Render("/"+m.searchQuery()) +
+		m.styles.Help.Render("  ("+helpLine(helpItem("edit", keys.Search), helpItem("clear", keys.Back))+")")
+}
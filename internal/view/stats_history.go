@@ -0,0 +1,126 @@
+package view
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/bShaak/habitui/internal/models"
+)
+
+// habitHistory indexes per-day completion totals by habit and day key.
+type habitHistory map[int64]map[string]models.DailyTotal
+
+func historyFromTotals(totals []models.DailyTotal) habitHistory {
+	h := make(habitHistory)
+	for _, t := range totals {
+		if h[t.HabitID] == nil {
+			h[t.HabitID] = make(map[string]models.DailyTotal)
+		}
+		h[t.HabitID][t.Day] = t
+	}
+	return h
+}
+
+// historyFromCompletions aggregates an in-memory completion list the way
+// Store.GetDailyTotals does in SQL.
+func historyFromCompletions(completions []models.Completion) habitHistory {
+	h := make(habitHistory)
+	for _, c := range completions {
+		day := c.Day()
+		if day == "" {
+			continue
+		}
+		if h[c.HabitID] == nil {
+			h[c.HabitID] = make(map[string]models.DailyTotal)
+		}
+		t := h[c.HabitID][day]
+		t.HabitID, t.Day = c.HabitID, day
+		t.Count++
+		t.Value += c.Value
+		h[c.HabitID][day] = t
+	}
+	return h
+}
+
+// amounts returns habit's progress per day, like amountsByDay.
+func (h habitHistory) amounts(habit models.Habit) map[string]float64 {
+	byDay := make(map[string]float64, len(h[habit.ID]))
+	for day, t := range h[habit.ID] {
+		byDay[day] = habit.Amount(t)
+	}
+	return byDay
+}
+
+// totalsInRange sums habit's check-ins and logged amount over [start, end].
+func (h habitHistory) totalsInRange(habit models.Habit, start, end time.Time) (int, float64) {
+	from, to := models.DayKey(start), models.DayKey(end)
+	count, amount := 0, 0.0
+	for day, t := range h[habit.ID] {
+		if from <= day && day <= to {
+			count += t.Count
+			amount += habit.Amount(t)
+		}
+	}
+	return count, amount
+}
+
+type statsGroup struct {
+	Tag   string
+	Size  int
+	Stats habitStats
+}
+
+// statsSnapshot is the computed content of the stats screen, rebuilt only
+// when its period, habits or data change rather than on every render.
+type statsSnapshot struct {
+	Period statsPeriod
+	Groups []statsGroup
+	Habits []habitStats
+}
+
+// refreshStats recomputes the stats snapshot, loading per-day totals from the
+// store when the cached history is missing or doesn't reach back far enough.
+func refreshStats(m Model) Model {
+	now := today()
+	period := m.statsPeriod()
+	snapshot := &statsSnapshot{Period: period}
+	if period.StartDate.IsZero() {
+		m.stats = snapshot
+		return m
+	}
+
+	// Streaks look back further than most periods; whole weeks keep weekly
+	// quotas at the edge of the range intact.
+	from := startOfWeek(minTime(period.StartDate, startOfDay(now).AddDate(-streakLookbackYears, 0, 0)))
+	if m.statsHistory == nil || from.Before(m.statsHistoryFrom) {
+		totals, err := m.store.GetDailyTotals(context.Background(), from, now)
+		if err != nil {
+			log.Printf("Error fetching stats totals: %s", err)
+			m.setError("Could not load stats")
+			m.stats = snapshot
+			return m
+		}
+		m.statsHistory = historyFromTotals(totals)
+		m.statsHistoryFrom = from
+	}
+
+	for _, tag := range habitTags(m.habits) {
+		var tagged []models.Habit
+		for _, h := range m.habits {
+			if h.HasTag(tag) {
+				tagged = append(tagged, h)
+			}
+		}
+		snapshot.Groups = append(snapshot.Groups, statsGroup{
+			Tag:   tag,
+			Size:  len(tagged),
+			Stats: calculateStatsForGroup(tagged, m.statsHistory, m.pauses, period),
+		})
+	}
+	for _, h := range m.habits {
+		snapshot.Habits = append(snapshot.Habits, calculateStatsForHabit(h, m.statsHistory, m.pauses, period))
+	}
+	m.stats = snapshot
+	return m
+}
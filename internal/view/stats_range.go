@@ -0,0 +1,114 @@
+package view
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/bShaak/habitui/internal/models"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+)
+
+type statsRangeFields struct {
+	From string
+	To   string
+}
+
+func buildStatsRangeForm(fields *statsRangeFields) *huh.Form {
+	return huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Title("From").
+				Key("from").
+				Value(&fields.From).
+				Validate(validateFormDay),
+			huh.NewInput().
+				Title("To").
+				Key("to").
+				Value(&fields.To).
+				Validate(func(str string) error {
+					if err := validateFormDay(str); err != nil {
+						return err
+					}
+					if strings.TrimSpace(str) < strings.TrimSpace(fields.From) {
+						return errors.New("end is before the start")
+					}
+					return nil
+				}),
+		),
+	).WithWidth(60).WithTheme(formTheme()).WithKeyMap(formKeyMap())
+}
+
+// openStatsRangeForm asks for a custom stats range, prefilled with the current
+// one or the month so far.
+func openStatsRangeForm(m Model) (Model, tea.Cmd) {
+	now := today()
+	fields := &statsRangeFields{
+		From: models.DayKey(time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())),
+		To:   models.DayKey(now),
+	}
+	if !m.statsCustom.StartDate.IsZero() {
+		fields.From = models.DayKey(m.statsCustom.StartDate)
+		fields.To = models.DayKey(m.statsCustom.EndDate)
+	}
+	m.statsRangeFields = fields
+	m.form = buildStatsRangeForm(fields)
+	applyFormSize(m.form, m.width, m.height)
+	m.scrollOffset = 0
+	m.screen = screenStatsRange
+	return m, m.form.Init()
+}
+
+func closeStatsRangeForm(m Model) Model {
+	m.form = nil
+	m.statsRangeFields = nil
+	m.screen = screenStats
+	return m
+}
+
+func updateStatsRange(m Model, msg tea.Msg) (tea.Model, tea.Cmd) {
+	if _, ok := msg.(tea.WindowSizeMsg); ok {
+		applyFormSize(m.form, m.width, m.height)
+		return m, nil
+	}
+	if m.statsRangeFields == nil {
+		return closeStatsRangeForm(m), nil
+	}
+
+	var cmds []tea.Cmd
+	form, cmd := m.form.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.form = f
+		cmds = append(cmds, cmd)
+	}
+	if m.form.State != huh.StateCompleted {
+		return m, tea.Batch(cmds...)
+	}
+
+	from, err1 := time.ParseInLocation(models.DayLayout, strings.TrimSpace(m.statsRangeFields.From), time.Local)
+	to, err2 := time.ParseInLocation(models.DayLayout, strings.TrimSpace(m.statsRangeFields.To), time.Local)
+	m = closeStatsRangeForm(m)
+	if err1 != nil || err2 != nil {
+		return m, nil
+	}
+	m.statsCustom = statsPeriod{StartDate: from, EndDate: endOfDay(to)}
+	m.statsTab = statsTabCustom
+	m.statsOffset = 0
+	return refreshStats(m), nil
+}
+
+func viewStatsRange(m Model) string {
+	s := m.styles
+	var b strings.Builder
+	b.WriteString(m.renderTitle())
+	b.WriteString("\n")
+	b.WriteString(m.appBoundaryView("Custom Stats Range"))
+	b.WriteString("\n\n")
+	var content strings.Builder
+	content.WriteString(m.form.View())
+	content.WriteString("\n\n")
+	content.WriteString(s.Help.Render(helpLine(helpItem("next / apply", submitKey()), helpItem("cancel", keys.Back))))
+	b.WriteString(s.ContentBox.Render(content.String()))
+	return s.Base.Render(b.String())
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
@@ -11,56 +12,92 @@ import (
 func updateStats(m Model, msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "q":
+		switch {
+		case key.Matches(msg, keys.Quit):
 			return m, tea.Quit
-		case "left":
+		case key.Matches(msg, keys.PrevTab):
 			if m.statsTab > 0 {
 				m.statsTab--
+				m.statsOffset = 0
+				return refreshStats(m), nil
 			}
-		case "right":
-			if m.statsTab < 2 {
+		case key.Matches(msg, keys.NextTab):
+			if m.statsTab < len(statsTabNames)-1 {
 				m.statsTab++
+				m.statsOffset = 0
+				return refreshStats(m), nil
 			}
+		case key.Matches(msg, keys.Prev):
+			if m.statsTab != statsTabAllTime && !m.statsPeriod().StartDate.IsZero() {
+				m.statsOffset++
+				return refreshStats(m), nil
+			}
+		case key.Matches(msg, keys.Next):
+			if m.statsTab == statsTabAllTime {
+				return m, nil
+			}
+			// Step forward while the next period has started; custom ranges
+			// may also move past where they were entered.
+			next := statsPeriodFor(m.statsTab, m.statsOffset-1, m.statsCustom, m.habits, today())
+			if (m.statsOffset > 0 || m.statsTab == statsTabCustom) &&
+				!next.StartDate.IsZero() && !next.StartDate.After(today()) {
+				m.statsOffset--
+				return refreshStats(m), nil
+			}
+		case key.Matches(msg, keys.CustomRange):
+			return openStatsRangeForm(m)
+		case key.Matches(msg, keys.TagFilter):
+			return refreshStats(cycleTagFilter(m)), nil
 		}
 	}
 	return m, nil
 }
 
+// statsPeriod is the period selected by the current tab and offset.
+func (m Model) statsPeriod() statsPeriod {
+	return statsPeriodFor(m.statsTab, m.statsOffset, m.statsCustom, m.habits, today())
+}
+
 func viewStats(m Model) string {
+	v, _ := renderStats(m)
+	return v
+}
+
+// renderStats draws the stats screen and records a zone for each tab.
+func renderStats(m Model) (string, layout) {
 	s := m.styles
+	var l layout
 	var b strings.Builder
 	b.WriteString(m.renderTitle())
 	b.WriteString("\n")
 
-	header := m.appBoundaryView("Habit Statistics")
+	header := m.appBoundaryView(m.withHabitFilters("Habit Statistics"))
 	b.WriteString(header)
 	b.WriteString("\n\n")
 
-	periods := getStatsPeriods()
-	allCompletions := m.statsCompletions
-
-	tabNames := []string{"Last 7 Days", "Last 30 Days", "Last Year"}
 	tabStyle := lipgloss.NewStyle().Foreground(text).Padding(0, 1)
 	activeTabStyle := tabStyle.Foreground(primary).Bold(true).Background(surface)
 
-	var tabs strings.Builder
-	for i, name := range tabNames {
+	for i, name := range statsTabNames {
+		style := tabStyle
 		if i == m.statsTab {
-			tabs.WriteString(activeTabStyle.Render(name))
-		} else {
-			tabs.WriteString(tabStyle.Render(name))
+			style = activeTabStyle
 		}
+		tab := style.Render(name)
+		l.mark(&b, zoneTab, 0, i, lipgloss.Width(tab))
+		b.WriteString(tab)
 	}
-	b.WriteString(tabs.String())
 	b.WriteString("\n\n")
 
-	period := periods[m.statsTab]
+	snapshot := m.stats
+	if snapshot == nil {
+		snapshot = refreshStats(m).stats
+	}
+	period := snapshot.Period
 
 	periodLabelStyle := lipgloss.NewStyle().
 		Foreground(primary).
-		Bold(true).
-		Width(20)
+		Bold(true)
 
 	statLabelStyle := lipgloss.NewStyle().
 		Foreground(subtext).
@@ -79,16 +116,37 @@ func viewStats(m Model) string {
 	content.WriteString(lipgloss.NewStyle().Foreground(muted).Render(separator))
 	content.WriteString("\n")
 
-	if len(m.habits) == 0 {
+	if period.StartDate.IsZero() {
+		content.WriteString(s.Help.Render(fmt.Sprintf("No custom range yet. Press '%s' to pick one.", helpKey(keys.CustomRange))))
+		content.WriteString("\n\n")
+	} else if len(m.habits) == 0 {
 		content.WriteString(s.Help.Render("No habits to show stats for."))
 	} else {
-		for _, habit := range m.habits {
-			stats := calculateStatsForHabit(habit, allCompletions, period)
+		for _, group := range snapshot.Groups {
+			stats := group.Stats
+
+			content.WriteString(lipgloss.NewStyle().Foreground(primary).Render(
+				fmt.Sprintf("#%s (%d habits)", group.Tag, group.Size)))
+			content.WriteString("\n")
+			content.WriteString(statLabelStyle.Render("  Completed: "))
+			content.WriteString(statValueStyle.Render(fmt.Sprintf("%d/%d", stats.GoalDaysMet, stats.ScheduledDays)))
+			content.WriteString(statLabelStyle.Render("Rate: "))
+			content.WriteString(statValueStyle.Render(formatRate(stats.CompletionRate)))
+			content.WriteString("\n")
+			content.WriteString(statLabelStyle.Render("  Current Streak: "))
+			content.WriteString(statValueStyle.Render(fmt.Sprintf("%d days", stats.CurrentStreak)))
+			content.WriteString(statLabelStyle.Render("Best Streak: "))
+			content.WriteString(statValueStyle.Render(fmt.Sprintf("%d days", stats.LongestStreak)))
+			content.WriteString("\n\n")
+		}
+
+		for _, stats := range snapshot.Habits {
+			habit := stats.Habit
 
 			habitColor := getHabitColor(habit.Color)
 			habitNameStyle := lipgloss.NewStyle().Foreground(habitColor)
 
-			habitName := truncateRunes(formatHabitLabel(habit), 20)
+			habitName := truncateRunes(habit.Label(), 20)
 
 			content.WriteString(habitNameStyle.Render(habitName))
 			content.WriteString("\n")
@@ -111,15 +169,21 @@ func viewStats(m Model) string {
 			content.WriteString(statValueStyle.Render(longestStr))
 			content.WriteString("\n")
 
+			if habit.IsQuantity() {
+				content.WriteString(statLabelStyle.Render("  Total: "))
+				content.WriteString(statValueStyle.Render(formatQuantity(stats.TotalAmount, habit.Unit)))
+				content.WriteString(statLabelStyle.Render("Avg / Day: "))
+				content.WriteString(statValueStyle.Render(formatQuantity(stats.AverageAmount, habit.Unit)))
+				content.WriteString("\n")
+			}
+
 			content.WriteString("\n")
 		}
 	}
 
-	helpText := "←/→: switch tabs  |  esc: back  |  q: quit"
-	help := s.Help.Render(helpText)
-	content.WriteString(help)
+	content.WriteString(m.shortHelp())
 
 	b.WriteString(s.ContentBox.Render(content.String()))
 
-	return s.Base.Render(b.String())
+	return s.Base.Render(b.String()), l.framed(s)
 }
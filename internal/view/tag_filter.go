@@ -0,0 +1,62 @@
+package view
+
+import (
+	"context"
+	"log"
+	"sort"
+
+	"github.com/bShaak/habitui/internal/models"
+)
+
+// cycleTagFilter steps the tag filter through "all" and each tag in use.
+func cycleTagFilter(m Model) Model {
+	tags, err := m.store.ListTags(context.Background())
+	if err != nil {
+		log.Printf("Error fetching tags: %s", err)
+		m.setError("Could not load tags")
+		return m
+	}
+	if len(tags) == 0 {
+		m.tagFilter = ""
+		m.setStatus("No tags yet — add some when editing a habit")
+		return m
+	}
+	next := tags[0]
+	if m.tagFilter != "" {
+		next = ""
+		for i, t := range tags {
+			if t == m.tagFilter && i+1 < len(tags) {
+				next = tags[i+1]
+				break
+			}
+		}
+	}
+	m.tagFilter = next
+	m = arrangeHabits(m)
+	m.statusMsg = ""
+	return m
+}
+
+// withTagFilter appends the active tag filter to a screen title.
+func (m Model) withTagFilter(title string) string {
+	if m.tagFilter == "" {
+		return title
+	}
+	return title + "  #" + m.tagFilter
+}
+
+// habitTags lists the distinct tags on habits, sorted.
+func habitTags(habits []models.Habit) []string {
+	seen := make(map[string]bool)
+	var tags []string
+	for _, h := range habits {
+		for _, t := range h.Tags {
+			if !seen[t] {
+				seen[t] = true
+				tags = append(tags, t)
+			}
+		}
+	}
+	sort.Strings(tags)
+	return tags
+}
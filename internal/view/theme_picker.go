@@ -0,0 +1,233 @@
+package view
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/bShaak/habitui/internal/theme"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// themePickerNameWidth fits the longest built-in name plus the cursor.
+const themePickerNameWidth = 20
+
+// openThemePicker lists every theme with the current one selected. The
+// active theme is remembered so esc can put it back.
+func openThemePicker(m Model) Model {
+	m.statusMsg = ""
+	m.themeRevert = currentTheme
+	m.themeCursor = 0
+	for i, t := range theme.Themes() {
+		if t.Name == currentTheme.Name {
+			m.themeCursor = i
+			break
+		}
+	}
+	m.scrollOffset = 0
+	m.screen = screenThemes
+	return m
+}
+
+// useTheme makes t the active palette and rebuilds the styles that bake
+// colors in.
+func useTheme(m Model, t theme.Theme) Model {
+	currentTheme = t
+	applyThemeColors()
+	m.styles = newStyles(m.lg)
+	return m
+}
+
+// previewTheme applies the theme under the cursor with the config's color
+// overrides, without saving anything.
+func previewTheme(m Model) Model {
+	themes := theme.Themes()
+	if m.themeCursor < 0 || m.themeCursor >= len(themes) {
+		return m
+	}
+	config := theme.Config{Theme: themes[m.themeCursor].Name}
+	if themeConfig != nil {
+		config.Base = themeConfig.Base
+	}
+	return useTheme(m, theme.GetTheme(&config))
+}
+
+// revertThemePicker restores the theme that was active when the picker opened.
+func revertThemePicker(m Model) Model {
+	m = useTheme(m, m.themeRevert)
+	m.scrollOffset = 0
+	m.screen = screenMain
+	return m
+}
+
+func applyThemePicker(m Model) Model {
+	if themeConfig == nil {
+		themeConfig = &theme.Config{}
+	}
+	themeConfig.Theme = currentTheme.Name
+	m.setStatus("Theme: " + currentTheme.Name)
+	if err := theme.SaveConfig(themeConfig); err != nil {
+		log.Printf("Error saving theme config: %s", err)
+		m.setError("Could not save theme")
+	}
+	m.scrollOffset = 0
+	m.screen = screenMain
+	return m
+}
+
+func updateThemePicker(m Model, msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, keys.Quit):
+			return revertThemePicker(m), tea.Quit
+		case key.Matches(msg, keys.Up):
+			if m.themeCursor > 0 {
+				m.themeCursor--
+				return previewTheme(m), nil
+			}
+		case key.Matches(msg, keys.Down):
+			if m.themeCursor < len(theme.Themes())-1 {
+				m.themeCursor++
+				return previewTheme(m), nil
+			}
+		case key.Matches(msg, keys.Toggle):
+			return applyThemePicker(m), nil
+		}
+	}
+	return m, nil
+}
+
+// themeSwatches renders one block per color slot of base.
+func themeSwatches(base theme.BaseColors) string {
+	colors := []string{
+		base.Text, base.Subtext, base.Muted, base.Surface, base.SurfaceAlt, base.Primary,
+		base.Red, base.Orange, base.Yellow, base.Green, base.Blue, base.Purple, base.Pink,
+	}
+	var b strings.Builder
+	for _, c := range colors {
+		b.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color(c)).Render("██"))
+	}
+	return b.String()
+}
+
+func viewThemePicker(m Model) string {
+	s := m.styles
+	var b strings.Builder
+	b.WriteString(m.renderTitle())
+	b.WriteString("\n")
+	b.WriteString(m.appBoundaryView("Themes"))
+	b.WriteString("\n\n")
+
+	var list strings.Builder
+	for i, t := range theme.Themes() {
+		cursor := " "
+		nameStyle := lipgloss.NewStyle().Foreground(subtext).Width(themePickerNameWidth)
+		if i == m.themeCursor {
+			cursor = ">"
+			nameStyle = nameStyle.Foreground(primary).Bold(true)
+		}
+		name := t.Name
+		if t.Name == m.themeRevert.Name {
+			name += " •"
+		}
+		list.WriteString(fmt.Sprintf("%s %s%s\n", cursor, nameStyle.Render(name), themeSwatches(t.Base)))
+	}
+
+	preview := viewThemePreview(m)
+	var content strings.Builder
+	if m.width <= 0 || lipgloss.Width(list.String())+lipgloss.Width(preview)+4+helpChrome <= m.width {
+		content.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, list.String(), "    ", preview))
+	} else {
+		content.WriteString(list.String())
+		content.WriteString("\n")
+		content.WriteString(preview)
+	}
+	content.WriteString("\n\n")
+	content.WriteString(m.shortHelp())
+
+	b.WriteString(s.ContentBox.Render(content.String()))
+	return s.Base.Render(b.String())
+}
+
+// viewThemePreview draws a small sample habit list and week so the palette
+// under the cursor can be judged on real UI elements.
+func viewThemePreview(m Model) string {
+	s := m.styles
+	type sample struct {
+		name, color, status string
+		week                string // one rune per weekday: ✓ done, ~ partial, · due, - off, ⊘ skipped
+	}
+	samples := []sample{
+		{"🧘 Meditate", "green", "✓ 🔥 12", "✓✓✓✓·-·"},
+		{"📚 Read", "blue", "✗ (12/30 pages)", "✓~✓·✓-·"},
+		{"🏃 Run", "orange", skipGlyph + " skipped", "-✓-⊘-✓-"},
+		{"💧 Water", "pink", "✓ (8/8 glasses)", "✓✓~✓✓✓·"},
+	}
+	nameWidth := 12
+
+	var b strings.Builder
+	b.WriteString(s.StatusHeader.Render("Preview"))
+	b.WriteString("\n\n")
+	for i, h := range samples {
+		cursor := " "
+		if i == 0 {
+			cursor = ">"
+		}
+		c := getHabitColor(h.color)
+		status := lipgloss.NewStyle().Foreground(c).Render(h.status)
+		if strings.Contains(h.status, "🔥") {
+			mark, streak, _ := strings.Cut(h.status, " ")
+			status = lipgloss.NewStyle().Foreground(c).Render(mark) + " " + lipgloss.NewStyle().Foreground(orange).Render(streak)
+		}
+		b.WriteString(fmt.Sprintf("%s %s %s\n", cursor, lipgloss.NewStyle().Foreground(c).Render(h.name), status))
+	}
+	b.WriteString("\n")
+
+	header := lipgloss.NewStyle().Foreground(blue).Bold(true).Width(4).Align(lipgloss.Center)
+	b.WriteString(strings.Repeat(" ", nameWidth))
+	for _, d := range weekdayOrder() {
+		b.WriteString(header.Render(d.String()[:2]))
+	}
+	b.WriteString("\n")
+	for row, h := range samples {
+		b.WriteString(lipgloss.NewStyle().Foreground(getHabitColor(h.color)).Width(nameWidth).Render(h.name))
+		for col, r := range []rune(h.week) {
+			cell := lipgloss.NewStyle().Width(4).Align(lipgloss.Center)
+			glyph := string(r)
+			switch r {
+			case '✓':
+				cell = cell.Foreground(green)
+			case '~':
+				cell, glyph = cell.Foreground(yellow), "✓"
+			case '⊘':
+				cell = cell.Foreground(blue)
+			default:
+				cell = cell.Foreground(muted)
+			}
+			if row == 1 && col == 3 {
+				cell = cell.Background(surface).Foreground(text)
+			}
+			b.WriteString(cell.Render(glyph))
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+	b.WriteString(statusStyle(true).Render("Theme: " + currentTheme.Name))
+	b.WriteString("\n")
+	b.WriteString(statusStyle(false).Render("Could not save habit"))
+	b.WriteString("\n")
+	b.WriteString(m.helpModel().ShortHelpView([]key.Binding{
+		describe(keys.Toggle, "toggle"),
+		describe(keys.Add, "add"),
+		describe(keys.Help, "more"),
+	}))
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(primary).
+		Padding(0, 1).
+		Render(b.String())
+}
@@ -0,0 +1,269 @@
+package view
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/bShaak/habitui/internal/models"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type vacationFormFields struct {
+	Start string
+	End   string
+	Note  string
+}
+
+func validateFormDay(str string) error {
+	if _, err := time.Parse(models.DayLayout, strings.TrimSpace(str)); err != nil {
+		return errors.New("use YYYY-MM-DD")
+	}
+	return nil
+}
+
+func buildVacationForm(fields *vacationFormFields) *huh.Form {
+	return huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Title("First day").
+				Key("start").
+				Value(&fields.Start).
+				Validate(validateFormDay),
+			huh.NewInput().
+				Title("Last day").
+				Key("end").
+				Value(&fields.End).
+				Validate(func(str string) error {
+					if err := validateFormDay(str); err != nil {
+						return err
+					}
+					if strings.TrimSpace(str) < strings.TrimSpace(fields.Start) {
+						return errors.New("last day is before the first day")
+					}
+					return nil
+				}),
+			huh.NewInput().
+				Title("Note").
+				Description("Optional, e.g. \"Flu\" or \"Beach trip\"").
+				Key("note").
+				CharLimit(80).
+				Value(&fields.Note),
+		),
+	).WithWidth(60).WithTheme(formTheme()).WithKeyMap(formKeyMap())
+}
+
+func openVacations(m Model) Model {
+	m.statusMsg = ""
+	m = loadVacations(m)
+	m.vacationCursor = 0
+	m.scrollOffset = 0
+	m.screen = screenVacations
+	return m
+}
+
+func loadVacations(m Model) Model {
+	vacations, err := m.store.ListVacations(context.Background())
+	if err != nil {
+		log.Printf("Error fetching vacations: %s", err)
+		return m
+	}
+	m.vacations = vacations
+	if m.vacationCursor >= len(m.vacations) {
+		m.vacationCursor = max(len(m.vacations)-1, 0)
+	}
+	return loadPauses(m)
+}
+
+func openVacationForm(m Model) (Model, tea.Cmd) {
+	today := models.DayKey(today())
+	m.vacationFields = &vacationFormFields{Start: today, End: today}
+	m.form = buildVacationForm(m.vacationFields)
+	applyFormSize(m.form, m.width, m.height)
+	m.statusMsg = ""
+	m.scrollOffset = 0
+	m.screen = screenAddVacation
+	return m, m.form.Init()
+}
+
+func closeVacationForm(m Model) Model {
+	m.form = nil
+	m.vacationFields = nil
+	m.screen = screenVacations
+	return m
+}
+
+func updateVacations(m Model, msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if m.confirmingDelete {
+			switch {
+			case key.Matches(msg, keys.Confirm):
+				return deleteSelectedVacation(m), nil
+			case key.Matches(msg, keys.Cancel, keys.Delete):
+				m.confirmingDelete = false
+			}
+			return m, nil
+		}
+
+		switch {
+		case key.Matches(msg, keys.Quit):
+			return m, tea.Quit
+		case key.Matches(msg, keys.Up):
+			if m.vacationCursor > 0 {
+				m.vacationCursor--
+			}
+		case key.Matches(msg, keys.Down):
+			if m.vacationCursor < len(m.vacations)-1 {
+				m.vacationCursor++
+			}
+		case key.Matches(msg, keys.Add):
+			return openVacationForm(m)
+		case key.Matches(msg, keys.Delete):
+			if len(m.vacations) > 0 {
+				m.confirmingDelete = true
+			}
+		}
+	}
+	return m, nil
+}
+
+func updateAddVacation(m Model, msg tea.Msg) (tea.Model, tea.Cmd) {
+	if _, ok := msg.(tea.WindowSizeMsg); ok {
+		applyFormSize(m.form, m.width, m.height)
+		return m, nil
+	}
+	if m.vacationFields == nil {
+		return closeVacationForm(m), nil
+	}
+
+	var cmds []tea.Cmd
+	form, cmd := m.form.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.form = f
+		cmds = append(cmds, cmd)
+	}
+	if m.form.State != huh.StateCompleted {
+		return m, tea.Batch(cmds...)
+	}
+
+	fields := m.vacationFields
+	vacation := &models.Vacation{
+		StartDay: strings.TrimSpace(fields.Start),
+		EndDay:   strings.TrimSpace(fields.End),
+		Note:     strings.TrimSpace(fields.Note),
+	}
+	if _, err := m.store.CreateVacation(context.Background(), vacation); err != nil {
+		log.Printf("Error creating vacation: %s", err)
+		m.setError("Could not add vacation: " + err.Error())
+		return closeVacationForm(m), nil
+	}
+	m = closeVacationForm(m)
+	m = loadVacations(m)
+	m = refreshStreakCompletions(m)
+	m.setStatus(fmt.Sprintf("Vacation added: %s", formatVacationRange(*vacation)))
+	return m, nil
+}
+
+func deleteSelectedVacation(m Model) Model {
+	m.confirmingDelete = false
+	if len(m.vacations) == 0 {
+		return m
+	}
+	v := m.vacations[m.vacationCursor]
+	if err := m.store.DeleteVacation(context.Background(), v.ID); err != nil {
+		log.Printf("Error deleting vacation: %s", err)
+		m.setError("Could not delete vacation")
+		return m
+	}
+	m = loadVacations(m)
+	m.statusMsg = ""
+	return m
+}
+
+func formatVacationRange(v models.Vacation) string {
+	start, err1 := time.Parse(models.DayLayout, v.StartDay)
+	end, err2 := time.Parse(models.DayLayout, v.EndDay)
+	if err1 != nil || err2 != nil {
+		return v.StartDay + " – " + v.EndDay
+	}
+	if start.Equal(end) {
+		return start.Format("Mon Jan 2, 2006")
+	}
+	return start.Format("Jan 2") + " – " + end.Format("Jan 2, 2006")
+}
+
+func viewVacations(m Model) string {
+	s := m.styles
+	var b strings.Builder
+	b.WriteString(m.renderTitle())
+	b.WriteString("\n")
+	b.WriteString(m.appBoundaryView("Vacations"))
+	b.WriteString("\n\n")
+
+	labelStyle := lipgloss.NewStyle().Foreground(subtext)
+	var content strings.Builder
+	content.WriteString(s.Help.Render("Vacation days pause every habit: they don't count toward or break streaks."))
+	content.WriteString("\n\n")
+	if len(m.vacations) == 0 {
+		content.WriteString(s.Help.Render(fmt.Sprintf("No vacations yet. Press '%s' to add one.", helpKey(keys.Add))))
+		content.WriteString("\n\n")
+	} else {
+		for i, v := range m.vacations {
+			cursor := " "
+			rangeStyle := lipgloss.NewStyle().Foreground(blue)
+			if i == m.vacationCursor {
+				cursor = ">"
+				rangeStyle = rangeStyle.Bold(true)
+			}
+			line := fmt.Sprintf("%s %s %s %s",
+				cursor,
+				vacationGlyph,
+				rangeStyle.Render(formatVacationRange(v)),
+				labelStyle.Render(fmt.Sprintf("(%d days)", v.Days())),
+			)
+			if v.Note != "" {
+				line += "  " + labelStyle.Render(v.Note)
+			}
+			content.WriteString(line)
+			content.WriteString("\n")
+		}
+		content.WriteString("\n")
+	}
+
+	if m.confirmingDelete && len(m.vacations) > 0 {
+		content.WriteString(lipgloss.NewStyle().Foreground(red).Bold(true).Render(
+			fmt.Sprintf("Delete vacation %s?  %s", formatVacationRange(m.vacations[m.vacationCursor]), confirmHelp()),
+		))
+	} else {
+		if m.statusMsg != "" {
+			content.WriteString(statusStyle(m.statusInfo).Render(m.statusMsg))
+			content.WriteString("\n")
+		}
+		content.WriteString(m.shortHelp())
+	}
+
+	b.WriteString(s.ContentBox.Render(content.String()))
+	return s.Base.Render(b.String())
+}
+
+func viewAddVacation(m Model) string {
+	s := m.styles
+	var b strings.Builder
+	b.WriteString(m.renderTitle())
+	b.WriteString("\n")
+	b.WriteString(m.appBoundaryView("Add Vacation"))
+	b.WriteString("\n\n")
+	var content strings.Builder
+	content.WriteString(m.form.View())
+	content.WriteString("\n\n")
+	content.WriteString(s.Help.Render(helpLine(helpItem("next / save", submitKey()), helpItem("cancel", keys.Back))))
+	b.WriteString(s.ContentBox.Render(content.String()))
+	return s.Base.Render(b.String())
+}
@@ -9,6 +9,8 @@ import (
 	"github.com/bShaak/habitui/internal/models"
 	"github.com/bShaak/habitui/internal/storage"
 	"github.com/bShaak/habitui/internal/theme"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/huh"
 	"github.com/charmbracelet/lipgloss"
@@ -22,11 +24,21 @@ const (
 	screenStats
 	screenCreateHabit
 	screenEditHabit
+	screenArchive
+	screenLogAmount
+	screenHeatmap
+	screenVacations
+	screenAddVacation
+	screenStatsRange
+	screenThemes
+	screenDayLog
+	screenDayLogEntry
+	screenHabitDetail
 )
 
 var (
-	currentTheme  theme.Theme
-	themeConfig   *theme.Config
+	currentTheme theme.Theme
+	themeConfig  *theme.Config
 )
 
 var (
@@ -45,15 +57,32 @@ var (
 	pink       lipgloss.Color
 )
 
-func initTheme() {
+// initTheme loads the config and user themes. It returns a status message
+// when some theme files could not be used.
+func initTheme() string {
+	status := ""
 	config, err := theme.LoadConfig()
 	if err != nil {
-		log.Printf("Error loading theme config: %s, using default", err)
-		config = &theme.Config{}
+		log.Printf("Error loading config: %s, using defaults", err)
+		defaults := theme.GetDefaultConfig()
+		config = &defaults
+		status = "Could not read the config; using defaults and the previous day boundary"
 	}
+	userThemes, err := theme.LoadUserThemes(theme.GetThemesDir())
+	if err != nil {
+		log.Printf("Error loading user themes: %s", err)
+		status = "Some themes could not be loaded; run 'habitui themes' for details"
+	}
+	theme.RegisterThemes(userThemes)
 	themeConfig = config
 	currentTheme = theme.GetTheme(config)
 	applyThemeColors()
+	initKeys(config)
+	if _, ok := weekStarts[strings.ToLower(config.WeekStart)]; config.WeekStart != "" && !ok {
+		log.Printf("Unknown week_start %q, using monday", config.WeekStart)
+		status = "Unknown week_start in config; weeks start on Monday"
+	}
+	return status
 }
 
 func applyThemeColors() {
@@ -73,22 +102,6 @@ func applyThemeColors() {
 	pink = lipgloss.Color(t.Pink)
 }
 
-func cycleTheme(m Model) Model {
-	if themeConfig == nil {
-		themeConfig = &theme.Config{}
-	}
-	next := theme.NextThemeName(currentTheme.Name)
-	themeConfig.Theme = next
-	currentTheme = theme.GetTheme(themeConfig)
-	applyThemeColors()
-	m.styles = newStyles(m.lg)
-	m.statusMsg = "Theme: " + currentTheme.Name
-	if err := theme.SaveConfig(themeConfig); err != nil {
-		log.Printf("Error saving theme config: %s", err)
-	}
-	return m
-}
-
 func getHabitColor(colorName string) lipgloss.Color {
 	colorKey := colorName
 	if colorKey == "" {
@@ -106,11 +119,14 @@ func getHabitColor(colorName string) lipgloss.Color {
 		"pink":   pink,
 	}
 
-	c, ok := colorMap[colorKey]
-	if !ok {
-		c = red
+	if c, ok := colorMap[colorKey]; ok {
+		return c
 	}
-	return c
+	// Hex and 256-color values are fixed and don't follow the theme.
+	if fixed, err := models.NormalizeColor(colorKey); err == nil {
+		return lipgloss.Color(fixed)
+	}
+	return red
 }
 
 type Styles struct {
@@ -160,27 +176,55 @@ func newStyles(lg *lipgloss.Renderer) *Styles {
 }
 
 type Model struct {
-	habits            []models.Habit
-	completions       []models.Completion
-	streakCompletions []models.Completion
-	statsCompletions  []models.Completion
-	store             storage.Store
-	form              *huh.Form
-	formFields        *habitFormFields
-	lg                *lipgloss.Renderer
-	styles            *Styles
-	screen            screen
-	cursor            int
-	weekStart         time.Time
-	weekCompletions   []models.Completion
-	calendarCol       int
-	scrollOffset      int
-	statsTab          int
-	width             int
-	height            int
-	confirmingDelete  bool
-	statusMsg         string
-	viewDay time.Time
+	allHabits          []models.Habit // every active habit, in store order
+	habits             []models.Habit // allHabits filtered and sorted for display
+	tagFilter          string
+	pauses             models.Pauses // skipped days and vacations
+	vacations          []models.Vacation
+	vacationCursor     int
+	vacationFields     *vacationFormFields
+	search             textinput.Model
+	searching          bool // search input has focus
+	completions        []models.Completion
+	streakCompletions  []models.Completion
+	statsHistory       habitHistory
+	statsHistoryFrom   time.Time
+	stats              *statsSnapshot
+	store              storage.Store
+	form               *huh.Form
+	formFields         *habitFormFields
+	amountEntry        *amountEntry
+	dayLog             *dayLogState
+	dayLogFields       *dayLogFields
+	detail             *habitDetail
+	lg                 *lipgloss.Renderer
+	styles             *Styles
+	screen             screen
+	cursor             int
+	weekStart          time.Time
+	weekCompletions    []models.Completion
+	calendarCol        int
+	scrollOffset       int
+	statsTab           int
+	statsOffset        int
+	statsCustom        statsPeriod
+	statsRangeFields   *statsRangeFields
+	themeCursor        int
+	themeRevert        theme.Theme // active theme when the picker opened
+	width              int
+	height             int
+	archivedHabits     []models.Habit
+	archiveSummaries   []archiveSummary // parallel to archivedHabits
+	archiveCursor      int
+	heatmapHabit       int
+	heatmapEnd         time.Time
+	heatmapCompletions []models.Completion
+	confirmingDelete   bool
+	showHelp           bool // full-screen key overlay
+	lastClick          lastClick
+	statusMsg          string
+	statusInfo         bool // statusMsg is a confirmation rather than an error
+	viewDay            time.Time
 }
 
 func (m Model) appBoundaryView(title string) string {
@@ -203,9 +247,14 @@ func (m Model) Close() error {
 	return m.store.Close()
 }
 
-func InitViewState() Model {
-	initTheme()
-	store, err := storage.OpenSQLite()
+// InitViewState opens the store with rollover, the day_ends_at main read
+// from the config (nil keeps the stored one), and loads today's habits.
+func InitViewState(rollover *time.Duration) Model {
+	themeStatus := initTheme()
+	if rollover == nil && themeStatus == "" {
+		themeStatus = "Invalid day_ends_at in config; keeping the previous day boundary"
+	}
+	store, err := storage.OpenSQLite(rollover)
 	if err != nil {
 		log.Fatalf("Error opening database: %s", err)
 	}
@@ -215,28 +264,29 @@ func InitViewState() Model {
 		log.Fatalf("Error fetching habits: %s", err)
 	}
 
-	completions, err := store.GetCompletionsByDate(context.Background(), time.Now())
+	completions, err := store.GetCompletionsByDate(context.Background(), today())
 	if err != nil {
 		log.Fatalf("Error fetching completions: %s", err)
 	}
 
-	now := time.Now()
-	weekStart := getMonday(now)
+	day := today()
+	weekStart := startOfWeek(day)
 	weekEnd := weekStart.AddDate(0, 0, 6)
 	weekCompletions, err := store.GetCompletionsByDateRange(context.Background(), weekStart, weekEnd)
 	if err != nil {
 		log.Fatalf("Error fetching week completions: %s", err)
 	}
 
-	streakCompletions, err := loadStreakCompletions(store, now)
+	streakCompletions, err := loadStreakCompletions(store, day)
 	if err != nil {
 		log.Fatalf("Error fetching streak completions: %s", err)
 	}
 
 	lg := lipgloss.DefaultRenderer()
 
-	return Model{
-		habits:            habits,
+	m := Model{
+		allHabits:         habits,
+		search:            newSearchInput(),
 		completions:       completions,
 		streakCompletions: streakCompletions,
 		store:             store,
@@ -247,8 +297,11 @@ func InitViewState() Model {
 		weekStart:         weekStart,
 		weekCompletions:   weekCompletions,
 		calendarCol:       0,
-		viewDay:           startOfDay(now),
+		viewDay:           day,
+		statusMsg:         themeStatus,
 	}
+	m = loadPauses(m)
+	return arrangeHabits(m)
 }
 
 const (
@@ -291,33 +344,69 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case dayRefreshTickMsg:
 		// Catch midnight rollover when the terminal stays focused and idle.
 		return refreshIfDayChanged(m), dayRefreshTick()
+	case tea.MouseMsg:
+		if m.form != nil {
+			return m, nil
+		}
+		return updateMouse(m, msg)
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "ctrl+c":
+		if msg.String() == "ctrl+c" {
 			return m, tea.Quit
-		case "esc":
+		}
+		if m.showHelp {
+			return updateHelp(m, msg)
+		}
+		switch {
+		case key.Matches(msg, keys.Help) && m.form == nil && !m.searching && !m.confirmingDelete:
+			return openHelp(m), nil
+		case key.Matches(msg, keys.Back):
 			if m.confirmingDelete {
 				m.confirmingDelete = false
 				return m, nil
 			}
-			completions, err := m.store.GetCompletionsByDate(context.Background(), time.Now())
+			if m.screen == screenLogAmount {
+				return closeAmountEntry(m), nil
+			}
+			if m.screen == screenAddVacation {
+				return closeVacationForm(m), nil
+			}
+			if m.screen == screenStatsRange {
+				return closeStatsRangeForm(m), nil
+			}
+			if m.screen == screenThemes {
+				return revertThemePicker(m), nil
+			}
+			if m.screen == screenDayLogEntry {
+				return closeDayLogForm(m), nil
+			}
+			if m.screen == screenDayLog {
+				return closeDayLog(m), nil
+			}
+			if m.screen == screenHabitDetail {
+				return closeHabitDetail(m), nil
+			}
+			if m.searchActive() && (m.screen == screenMain || m.screen == screenCalendar) {
+				return clearSearch(m), nil
+			}
+			completions, err := m.store.GetCompletionsByDate(context.Background(), today())
 			if err != nil {
 				log.Printf("Error fetching today's completions: %s", err)
 			}
 			m.completions = completions
 			m = refreshStreakCompletions(m)
+			m = arrangeHabits(m)
 			m.form = nil
 			m.formFields = nil
 			m.confirmingDelete = false
 			m.scrollOffset = 0
 			m.screen = screenMain
 			return m, nil
-		case "pgup", "ctrl+u":
+		case key.Matches(msg, keys.PageUp):
 			if m.form == nil {
 				m.scrollOffset = clampScroll(m.scrollOffset-pageScrollAmount(m), 0, maxScroll(m))
 				return m, nil
 			}
-		case "pgdown", "ctrl+d":
+		case key.Matches(msg, keys.PageDown):
 			if m.form == nil {
 				m.scrollOffset = clampScroll(m.scrollOffset+pageScrollAmount(m), 0, maxScroll(m))
 				return m, nil
@@ -337,6 +426,26 @@ func (m Model) updateScreen(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return updateCreateHabit(m, msg)
 	case screenEditHabit:
 		return updateEditHabit(m, msg)
+	case screenArchive:
+		return updateArchive(m, msg)
+	case screenLogAmount:
+		return updateLogAmount(m, msg)
+	case screenHeatmap:
+		return updateHeatmap(m, msg)
+	case screenVacations:
+		return updateVacations(m, msg)
+	case screenAddVacation:
+		return updateAddVacation(m, msg)
+	case screenStatsRange:
+		return updateStatsRange(m, msg)
+	case screenThemes:
+		return updateThemePicker(m, msg)
+	case screenDayLog:
+		return updateDayLog(m, msg)
+	case screenDayLogEntry:
+		return updateDayLogEntry(m, msg)
+	case screenHabitDetail:
+		return updateHabitDetail(m, msg)
 	default:
 		return updateMain(m, msg)
 	}
@@ -352,6 +461,9 @@ func (m Model) View() string {
 }
 
 func (m Model) screenContent() string {
+	if m.showHelp {
+		return viewHelp(m)
+	}
 	switch m.screen {
 	case screenCalendar:
 		return viewCalendar(m)
@@ -361,6 +473,26 @@ func (m Model) screenContent() string {
 		return viewCreateHabit(m)
 	case screenEditHabit:
 		return viewEditHabit(m)
+	case screenArchive:
+		return viewArchive(m)
+	case screenLogAmount:
+		return viewLogAmount(m)
+	case screenHeatmap:
+		return viewHeatmap(m)
+	case screenVacations:
+		return viewVacations(m)
+	case screenAddVacation:
+		return viewAddVacation(m)
+	case screenStatsRange:
+		return viewStatsRange(m)
+	case screenThemes:
+		return viewThemePicker(m)
+	case screenDayLog:
+		return viewDayLog(m)
+	case screenDayLogEntry:
+		return viewDayLogEntry(m)
+	case screenHabitDetail:
+		return viewHabitDetail(m)
 	default:
 		return viewMain(m)
 	}
@@ -414,20 +546,24 @@ func applyViewport(content string, height, offset int) string {
 }
 
 func refreshStreakCompletions(m Model) Model {
-	completions, err := loadStreakCompletions(m.store, time.Now())
+	completions, err := loadStreakCompletions(m.store, today())
 	if err != nil {
 		log.Printf("Error fetching streak completions: %s", err)
 		return m
 	}
 	m.streakCompletions = completions
+	// Completions changed, so cached stats totals are stale.
+	m.statsHistory = nil
 	return m
 }
 
+// needsDayRefresh reports whether the day shown is over, which happens at the
+// configured rollover rather than at midnight.
 func needsDayRefresh(viewDay, now time.Time) bool {
 	if viewDay.IsZero() {
 		return true
 	}
-	return !startOfDay(viewDay).Equal(startOfDay(now))
+	return !startOfDay(viewDay).Equal(models.HabitDay(now))
 }
 
 func refreshIfDayChanged(m Model) Model {
@@ -439,7 +575,8 @@ func refreshIfDayChanged(m Model) Model {
 }
 
 func refreshForDay(m Model, now time.Time) Model {
-	completions, err := m.store.GetCompletionsByDate(context.Background(), now)
+	day := models.HabitDay(now)
+	completions, err := m.store.GetCompletionsByDate(context.Background(), day)
 	if err != nil {
 		log.Printf("Error fetching today's completions: %s", err)
 		// Leave viewDay unchanged so the next focus/tick retries.
@@ -447,7 +584,7 @@ func refreshForDay(m Model, now time.Time) Model {
 	}
 	m.completions = completions
 
-	weekStart := getMonday(now)
+	weekStart := startOfWeek(day)
 	weekEnd := weekStart.AddDate(0, 0, 6)
 	weekCompletions, err := m.store.GetCompletionsByDateRange(context.Background(), weekStart, weekEnd)
 	if err != nil {
@@ -458,7 +595,11 @@ func refreshForDay(m Model, now time.Time) Model {
 	}
 
 	m = refreshStreakCompletions(m)
-	m.viewDay = startOfDay(now)
+	m = arrangeHabits(m)
+	if m.screen == screenStats {
+		m = refreshStats(m)
+	}
+	m.viewDay = day
 	return m
 }
 